@@ -0,0 +1,79 @@
+// Command breachedpasswords builds the BREACHED_PASSWORDS_DIR range files
+// from a plain list of passwords, one per line. Ranges downloaded from Have I
+// Been Pwned can be used as they are and do not need this tool.
+//
+//	go run ./cmd/breachedpasswords -in rockyou.txt -out ./breached
+//	BREACHED_PASSWORDS_DIR=./breached go run .
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"finalProject/helper"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	in := flag.String("in", "-", "password list, - reads standard input")
+	out := flag.String("out", "breached", "directory to write the range files to")
+	flag.Parse()
+
+	var input io.Reader = os.Stdin
+	if *in != "-" {
+		file, err := os.Open(*in)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	ranges := map[string]map[string]int{}
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		password := strings.TrimRight(scanner.Text(), "\r")
+		if password == "" {
+			continue
+		}
+
+		sum := sha1.Sum([]byte(password))
+		hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+		prefix, suffix := hash[:helper.BreachedPasswordPrefixLength], hash[helper.BreachedPasswordPrefixLength:]
+
+		if ranges[prefix] == nil {
+			ranges[prefix] = map[string]int{}
+		}
+		ranges[prefix][suffix]++
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	err := os.MkdirAll(*out, 0o755)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for prefix, suffixes := range ranges {
+		lines := make([]string, 0, len(suffixes))
+		for suffix, count := range suffixes {
+			lines = append(lines, fmt.Sprintf("%s:%d", suffix, count))
+		}
+		sort.Strings(lines)
+
+		err = os.WriteFile(filepath.Join(*out, prefix+".txt"), []byte(strings.Join(lines, "\n")+"\n"), 0o644)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	log.Printf("wrote %d range files to %s", len(ranges), *out)
+}
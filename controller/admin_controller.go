@@ -0,0 +1,252 @@
+package controller
+
+import (
+	"finalProject/model"
+	"finalProject/service"
+	"net/http"
+
+	Valid "github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminController struct {
+	AdminService service.AdminService
+}
+
+func NewAdminController(adminService service.AdminService) *AdminController {
+	return &AdminController{
+		AdminService: adminService,
+	}
+}
+
+// SetRole godoc
+//
+//		@Summary			Set User Role
+//		@Description		Change the role of a user to user, moderator or admin. Admin only. The user is logged out everywhere and gets the new role on the next login
+//		@Tags				Admin
+//		@Accept				json
+//		@Produce			json
+//		@Param				user_id	path			string 	true		"User ID"
+//		@Param				request body			model.UserSetRoleRequest	true	"role is user, moderator or admin"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			403		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/admin/users/{user_id}/role	[put]
+func (ac *AdminController) SetRole(ctx *gin.Context) {
+	var request model.UserSetRoleRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	valid, err := Valid.ValidateStruct(request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if !valid {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.Param("user_id")
+	adminID := ctx.GetString("user_id")
+
+	response, err := ac.AdminService.SetRole(request, userID, adminID)
+	if err != nil {
+		if err == model.ErrorCannotChangeOwnRole {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusForbidden,
+					Message: http.StatusText(http.StatusForbidden),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
+
+// SetAge godoc
+//
+//		@Summary			Set User Age
+//		@Description		Correct the age of a user. Admin only. The age counts up from today and the user can no longer change it. A restricted age makes the account private
+//		@Tags				Admin
+//		@Accept				json
+//		@Produce			json
+//		@Param				user_id	path			string 	true		"User ID"
+//		@Param				request body			model.UserSetAgeRequest	true	"age between 8 and 99"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			403		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/admin/users/{user_id}/age	[put]
+func (ac *AdminController) SetAge(ctx *gin.Context) {
+	var request model.UserSetAgeRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	valid, err := Valid.ValidateStruct(request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if !valid {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.Param("user_id")
+
+	response, err := ac.AdminService.SetAge(request, userID)
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
+
+// UnlockLogin godoc
+//
+//		@Summary			Unlock User Login
+//		@Description		Lift a login lockout caused by too many failed attempts. Admin only
+//		@Tags				Admin
+//		@Produce			json
+//		@Param				user_id	path			string 	true		"User ID"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			403		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/admin/users/{user_id}/unlock	[post]
+func (ac *AdminController) UnlockLogin(ctx *gin.Context) {
+	userID := ctx.Param("user_id")
+
+	err := ac.AdminService.UnlockLogin(userID)
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Login unlocked",
+	})
+}
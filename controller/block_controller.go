@@ -0,0 +1,333 @@
+package controller
+
+import (
+	"finalProject/model"
+	"finalProject/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BlockController struct {
+	BlockService service.BlockService
+}
+
+func NewBlockController(blockService service.BlockService) *BlockController {
+	return &BlockController{
+		BlockService: blockService,
+	}
+}
+
+// Block godoc
+//
+//		@Summary			Block User
+//		@Description		Block a user. Both users stop seeing each other's photos and comments, cannot comment on each other's photos and the follows between them are removed
+//		@Tags				User
+//		@Produce			json
+//		@Param				username	path			string 	true		"Username"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/users/{username}/block	[post]
+func (bc *BlockController) Block(ctx *gin.Context) {
+	username := ctx.Param("username")
+	userID := ctx.GetString("user_id")
+
+	err := bc.BlockService.Block(username, userID)
+	if err != nil {
+		if err == model.ErrorCannotBlockSelf {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusBadRequest,
+					Message: http.StatusText(http.StatusBadRequest),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Block success",
+	})
+}
+
+// Unblock godoc
+//
+//		@Summary			Unblock User
+//		@Description		Remove a block. Follows removed by the block are not restored
+//		@Tags				User
+//		@Produce			json
+//		@Param				username	path			string 	true		"Username"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/users/{username}/block	[delete]
+func (bc *BlockController) Unblock(ctx *gin.Context) {
+	username := ctx.Param("username")
+	userID := ctx.GetString("user_id")
+
+	err := bc.BlockService.Unblock(username, userID)
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Unblock success",
+	})
+}
+
+// Mute godoc
+//
+//		@Summary			Mute User
+//		@Description		Hide the photos and comments of a user from your listings. The muted user is not notified
+//		@Tags				User
+//		@Produce			json
+//		@Param				username	path			string 	true		"Username"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/users/{username}/mute	[post]
+func (bc *BlockController) Mute(ctx *gin.Context) {
+	username := ctx.Param("username")
+	userID := ctx.GetString("user_id")
+
+	err := bc.BlockService.Mute(username, userID)
+	if err != nil {
+		if err == model.ErrorCannotMuteSelf {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusBadRequest,
+					Message: http.StatusText(http.StatusBadRequest),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Mute success",
+	})
+}
+
+// Unmute godoc
+//
+//		@Summary			Unmute User
+//		@Description		Show the photos and comments of a muted user again
+//		@Tags				User
+//		@Produce			json
+//		@Param				username	path			string 	true		"Username"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/users/{username}/mute	[delete]
+func (bc *BlockController) Unmute(ctx *gin.Context) {
+	username := ctx.Param("username")
+	userID := ctx.GetString("user_id")
+
+	err := bc.BlockService.Unmute(username, userID)
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Unmute success",
+	})
+}
+
+// GetBlocked godoc
+//
+//		@Summary			List Blocked Users
+//		@Description		List the users the logged in user blocked, latest first
+//		@Tags				User
+//		@Produce			json
+//		@Param				page		query			int 	false		"page number, starts at 1"
+//		@Param				limit		query			int 	false		"page size, default 20, at most 100"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/blocks	[get]
+func (bc *BlockController) GetBlocked(ctx *gin.Context) {
+	var query model.PageQuery
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.GetString("user_id")
+
+	response, err := bc.BlockService.GetBlocked(userID, query)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
+
+// GetMuted godoc
+//
+//		@Summary			List Muted Users
+//		@Description		List the users the logged in user muted, latest first
+//		@Tags				User
+//		@Produce			json
+//		@Param				page		query			int 	false		"page number, starts at 1"
+//		@Param				limit		query			int 	false		"page size, default 20, at most 100"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/mutes	[get]
+func (bc *BlockController) GetMuted(ctx *gin.Context) {
+	var query model.PageQuery
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.GetString("user_id")
+
+	response, err := bc.BlockService.GetMuted(userID, query)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
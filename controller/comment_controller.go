@@ -31,6 +31,7 @@ func NewCommentController(commentService service.CommentService) *CommentControl
 //		@Success			201		{object}		model.SuccessResponse
 //		@Failure			400		{object}		model.FailedResponse
 //		@Failure			401		{object}		model.FailedResponse
+//		@Failure			403		{object}		model.FailedResponse
 //		@Failure			404		{object}		model.FailedResponse
 //		@Failure			401		{object}		model.FailedResponse
 //		@Security			Bearer
@@ -86,6 +87,25 @@ func (cc *CommentController) CreateComment(ctx *gin.Context) {
 
 	comment, err := cc.CommentService.CreateComment(request, userID.(string), photoID)
 	if err != nil {
+		if err == model.ErrorPrivateAccount {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusForbidden,
+					Message: http.StatusText(http.StatusForbidden),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
 			Meta: model.Meta{
 				Code:    http.StatusInternalServerError,
@@ -118,7 +138,7 @@ func (cc *CommentController) CreateComment(ctx *gin.Context) {
 //		@Security			Bearer
 //	 @Router				/mygram/comments/get/all	[get]
 func (cc *CommentController) GetAllComment(ctx *gin.Context) {
-	allComment, err := cc.CommentService.GetAll()
+	allComment, err := cc.CommentService.GetAll(ctx.GetString("user_id"))
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
 			Meta: model.Meta{
@@ -150,16 +170,26 @@ func (cc *CommentController) GetAllComment(ctx *gin.Context) {
 //		@Param				comment_id	path			string 	true		"insert your Comment ID"
 //		@Success			201		{object}	model.SuccessResponse
 //		@Failure			401		{object}	model.FailedResponse
+//		@Failure			403		{object}	model.FailedResponse
 //		@Failure			404		{object}	model.FailedResponse
 //		@Failure			500		{object}	model.FailedResponse
 //		@Security			Bearer
 //	 @Router				/mygram/comments/get/{comment_id}	[get]
 func (cc *CommentController) GetOneComment(ctx *gin.Context) {
 	CommentID := ctx.Param("comment_id")
-	OneComment, err := cc.CommentService.GetOne(CommentID)
+	OneComment, err := cc.CommentService.GetOne(CommentID, ctx.GetString("user_id"))
 
 	if err != nil {
-		if err == model.ErrorNotFound {
+		if err == model.ErrorPrivateAccount {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusForbidden,
+					Message: http.StatusText(http.StatusForbidden),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorNotFound {
 			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
 				Meta: model.Meta{
 					Code:    http.StatusNotFound,
@@ -190,7 +220,7 @@ func (cc *CommentController) GetOneComment(ctx *gin.Context) {
 // CommentPhoto godoc
 //
 //		@Summary			Update Comment
-//		@Description		Update single Comment by input Social Media ID
+//		@Description		Update single Comment by input Social Media ID. Moderators and admins can update any comment
 //		@Tags				Comment
 //		@Accept				json
 //		@Produce			json
@@ -253,7 +283,7 @@ func (cc *CommentController) UpdateComment(ctx *gin.Context) {
 		return
 	}
 
-	res, err := cc.CommentService.Update(UpComment, CommentId, userId.(string))
+	res, err := cc.CommentService.Update(UpComment, CommentId, userId.(string), ctx.GetString("role"))
 	if err != nil {
 		if err == model.ErrorNotFound {
 			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
@@ -300,7 +330,7 @@ func (cc *CommentController) UpdateComment(ctx *gin.Context) {
 // DeleteComment godoc
 //
 //		@Summary			Delete Comment
-//		@Description		Delete Comment by input Social Media ID
+//		@Description		Delete Comment by input Social Media ID. The comment can be restored from the trash for 30 days. Moderators and admins can delete any comment, only a moderator can restore it then
 //		@Tags				Comment
 //		@Accept				json
 //		@Produce			json
@@ -327,7 +357,7 @@ func (cc *CommentController) DeleteComment(ctx *gin.Context) {
 		return
 	}
 
-	err := cc.CommentService.Delete(commentID, UserID.(string))
+	err := cc.CommentService.Delete(commentID, UserID.(string), ctx.GetString("role"))
 	if err != nil {
 		if err == model.ErrorNotFound {
 			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"finalProject/model"
+	"finalProject/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DataExportController struct {
+	DataExportService service.DataExportService
+}
+
+func NewDataExportController(dataExportService service.DataExportService) *DataExportController {
+	return &DataExportController{
+		DataExportService: dataExportService,
+	}
+}
+
+// RequestExport godoc
+//
+//		@Summary			Request Data Export
+//		@Description		Start building a ZIP with the profile, photos, comments written and received and social media as JSON and CSV. Returns the running export when one was already requested
+//		@Tags				User
+//		@Produce			json
+//		@Success			202		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/exports	[post]
+func (dc *DataExportController) RequestExport(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	response, err := dc.DataExportService.Request(userID)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusAccepted,
+			Message: http.StatusText(http.StatusAccepted),
+		},
+		Data: response,
+	})
+}
+
+// GetExport godoc
+//
+//		@Summary			Get Data Export
+//		@Description		Show the status of a data export, the download url is set once it is ready
+//		@Tags				User
+//		@Produce			json
+//		@Param				export_id	path			string 	true		"Export ID"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/exports/{export_id}	[get]
+func (dc *DataExportController) GetExport(ctx *gin.Context) {
+	exportID := ctx.Param("export_id")
+	userID := ctx.GetString("user_id")
+
+	response, err := dc.DataExportService.GetStatus(exportID, userID)
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
+
+// DownloadExport godoc
+//
+//		@Summary			Download Data Export
+//		@Description		Download the ZIP of a ready data export until its link expires
+//		@Tags				User
+//		@Produce			application/zip
+//		@Param				export_id	path			string 	true		"Export ID"
+//		@Success			200		{file}		file
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			409		{object}		model.FailedResponse
+//		@Failure			410		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/exports/{export_id}/download	[get]
+func (dc *DataExportController) DownloadExport(ctx *gin.Context) {
+	exportID := ctx.Param("export_id")
+	userID := ctx.GetString("user_id")
+
+	export, err := dc.DataExportService.Download(exportID, userID)
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorExportNotReady {
+			ctx.AbortWithStatusJSON(http.StatusConflict, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusConflict,
+					Message: http.StatusText(http.StatusConflict),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorExportExpired {
+			ctx.AbortWithStatusJSON(http.StatusGone, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusGone,
+					Message: http.StatusText(http.StatusGone),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.FileAttachment(export.FilePath, "mygram-export-"+export.ID+".zip")
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"finalProject/model"
+	"finalProject/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedController struct {
+	FeedService service.FeedService
+}
+
+func NewFeedController(feedService service.FeedService) *FeedController {
+	return &FeedController{
+		FeedService: feedService,
+	}
+}
+
+// GetFeed godoc
+//
+//		@Summary			Get Feed
+//		@Description		Show the photos of the users you follow and your own photos, newest first. Pass the next_cursor of a page as cursor to get the next page, an empty next_cursor means the last page
+//		@Tags				Photo
+//		@Produce			json
+//		@Param				cursor		query			string 	false		"next_cursor of the previous page"
+//		@Param				limit		query			int 	false		"page size, default 20, at most 100"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/feed	[get]
+func (fc *FeedController) GetFeed(ctx *gin.Context) {
+	var query model.CursorQuery
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.GetString("user_id")
+
+	response, err := fc.FeedService.GetFeed(userID, query)
+	if err != nil {
+		if err == model.ErrorInvalidCursor {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusBadRequest,
+					Message: http.StatusText(http.StatusBadRequest),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
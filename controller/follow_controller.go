@@ -0,0 +1,480 @@
+package controller
+
+import (
+	"finalProject/model"
+	"finalProject/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FollowController struct {
+	FollowService service.FollowService
+}
+
+func NewFollowController(followService service.FollowService) *FollowController {
+	return &FollowController{
+		FollowService: followService,
+	}
+}
+
+// Follow godoc
+//
+//		@Summary			Follow User
+//		@Description		Follow a user. Following someone already followed succeeds without changes. Following a private account sends a follow request instead, the status in the response is "following" or "requested"
+//		@Tags				User
+//		@Produce			json
+//		@Param				username	path			string 	true		"Username"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/users/{username}/follow	[post]
+func (fc *FollowController) Follow(ctx *gin.Context) {
+	username := ctx.Param("username")
+	userID := ctx.GetString("user_id")
+
+	response, err := fc.FollowService.Follow(username, userID)
+	if err != nil {
+		if err == model.ErrorCannotFollowSelf {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusBadRequest,
+					Message: http.StatusText(http.StatusBadRequest),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
+
+// Unfollow godoc
+//
+//		@Summary			Unfollow User
+//		@Description		Stop following a user or withdraw a pending follow request. Unfollowing someone not followed succeeds without changes
+//		@Tags				User
+//		@Produce			json
+//		@Param				username	path			string 	true		"Username"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/users/{username}/follow	[delete]
+func (fc *FollowController) Unfollow(ctx *gin.Context) {
+	username := ctx.Param("username")
+	userID := ctx.GetString("user_id")
+
+	err := fc.FollowService.Unfollow(username, userID)
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Unfollow success",
+	})
+}
+
+// GetFollowers godoc
+//
+//		@Summary			List Followers
+//		@Description		List the users following this user, latest follow first
+//		@Tags				User
+//		@Produce			json
+//		@Param				username	path			string 	true		"Username"
+//		@Param				page		query			int 	false		"page number, starts at 1"
+//		@Param				limit		query			int 	false		"page size, default 20, at most 100"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			403		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/users/{username}/followers	[get]
+func (fc *FollowController) GetFollowers(ctx *gin.Context) {
+	var query model.PageQuery
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	username := ctx.Param("username")
+
+	response, err := fc.FollowService.GetFollowers(username, ctx.GetString("user_id"), query)
+	if err != nil {
+		if err == model.ErrorPrivateAccount {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusForbidden,
+					Message: http.StatusText(http.StatusForbidden),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
+
+// GetFollowing godoc
+//
+//		@Summary			List Following
+//		@Description		List the users this user follows, latest follow first
+//		@Tags				User
+//		@Produce			json
+//		@Param				username	path			string 	true		"Username"
+//		@Param				page		query			int 	false		"page number, starts at 1"
+//		@Param				limit		query			int 	false		"page size, default 20, at most 100"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			403		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/users/{username}/following	[get]
+func (fc *FollowController) GetFollowing(ctx *gin.Context) {
+	var query model.PageQuery
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	username := ctx.Param("username")
+
+	response, err := fc.FollowService.GetFollowing(username, ctx.GetString("user_id"), query)
+	if err != nil {
+		if err == model.ErrorPrivateAccount {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusForbidden,
+					Message: http.StatusText(http.StatusForbidden),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
+
+// GetFollowRequests godoc
+//
+//		@Summary			List Follow Requests
+//		@Description		List the pending requests to follow the logged in user, latest first
+//		@Tags				User
+//		@Produce			json
+//		@Param				page		query			int 	false		"page number, starts at 1"
+//		@Param				limit		query			int 	false		"page size, default 20, at most 100"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/follow-requests	[get]
+func (fc *FollowController) GetFollowRequests(ctx *gin.Context) {
+	var query model.PageQuery
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.GetString("user_id")
+
+	response, err := fc.FollowService.GetFollowRequests(userID, query)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
+
+// AcceptFollowRequest godoc
+//
+//		@Summary			Accept Follow Request
+//		@Description		Accept the pending follow request of a user, who then becomes a follower
+//		@Tags				User
+//		@Produce			json
+//		@Param				username	path			string 	true		"Username of the requester"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/follow-requests/{username}/accept	[post]
+func (fc *FollowController) AcceptFollowRequest(ctx *gin.Context) {
+	username := ctx.Param("username")
+	userID := ctx.GetString("user_id")
+
+	err := fc.FollowService.AcceptFollowRequest(username, userID)
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Follow request accepted",
+	})
+}
+
+// RejectFollowRequest godoc
+//
+//		@Summary			Reject Follow Request
+//		@Description		Reject the pending follow request of a user
+//		@Tags				User
+//		@Produce			json
+//		@Param				username	path			string 	true		"Username of the requester"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/follow-requests/{username}/reject	[post]
+func (fc *FollowController) RejectFollowRequest(ctx *gin.Context) {
+	username := ctx.Param("username")
+	userID := ctx.GetString("user_id")
+
+	err := fc.FollowService.RejectFollowRequest(username, userID)
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Follow request rejected",
+	})
+}
+
+// SetPrivacy godoc
+//
+//		@Summary			Set Account Privacy
+//		@Description		Make the account private or public. Photos, comments and the profile of a private account are only shown to approved followers. Making the account public accepts every pending follow request
+//		@Tags				User
+//		@Accept				json
+//		@Produce			json
+//		@Param				request body		model.UserPrivacyRequest	true	"Privacy request is required"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/me/privacy	[put]
+func (fc *FollowController) SetPrivacy(ctx *gin.Context) {
+	var request model.UserPrivacyRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.GetString("user_id")
+
+	err = fc.FollowService.SetPrivacy(request, userID)
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Privacy updated",
+	})
+}
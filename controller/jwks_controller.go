@@ -0,0 +1,21 @@
+package controller
+
+import (
+	"finalProject/helper"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSController godoc
+//
+//		@Summary			JSON Web Key Set
+//		@Description		Public keys used to sign MyGram access tokens, so other services can verify them without a shared secret. The same keys sign two-factor challenge and email verification tokens, so only accept tokens whose typ claim is "access"
+//		@Tags				Auth
+//		@Produce			json
+//		@Success			200		{object}		model.JSONWebKeySet
+//	 @Router				/.well-known/jwks.json	[get]
+func JWKSController(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, helper.PublicKeySet())
+}
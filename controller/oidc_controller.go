@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"finalProject/model"
+	"finalProject/oidc"
+	"finalProject/service"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oidcStateCookie ties the provider callback to the browser that started the login.
+const (
+	oidcStateCookie     = "mygram_oidc_state"
+	oidcStateCookiePath = "/mygram/user/oidc"
+	oidcStateCookieAge  = 600
+)
+
+type OIDCController struct {
+	OIDCService service.OIDCService
+}
+
+func NewOIDCController(oidcService service.OIDCService) *OIDCController {
+	return &OIDCController{
+		OIDCService: oidcService,
+	}
+}
+
+// OIDCLogin godoc
+//
+//		@Summary			Login With External Provider
+//		@Description		Redirect to the configured OpenID Connect provider. Open it in a browser, the provider sends the user back to /mygram/user/oidc/callback
+//		@Tags				User
+//		@Success			302
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//	 @Router				/mygram/user/oidc/login	[get]
+func (oc *OIDCController) OIDCLogin(ctx *gin.Context) {
+	authURL, state, err := oc.OIDCService.StartLogin()
+	if err != nil {
+		if err == model.ErrorOIDCNotConfigured {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.SetCookie(oidcStateCookie, state, oidcStateCookieAge, oidcStateCookiePath, "", secureCookie(), true)
+	ctx.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback godoc
+//
+//		@Summary			External Provider Callback
+//		@Description		Finish the login started with /mygram/user/oidc/login. The first login creates a MyGram account, an existing account is only linked when both sides verified the same email
+//		@Tags				User
+//		@Produce			json
+//		@Param				code	query			string 	false		"authorization code from the provider"
+//		@Param				state	query			string 	true		"state from the provider redirect"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			403		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			409		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//	 @Router				/mygram/user/oidc/callback	[get]
+func (oc *OIDCController) OIDCCallback(ctx *gin.Context) {
+	var request model.OIDCCallbackRequest
+	err := ctx.ShouldBindQuery(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	cookieState, _ := ctx.Cookie(oidcStateCookie)
+	ctx.SetCookie(oidcStateCookie, "", -1, oidcStateCookiePath, "", secureCookie(), true)
+
+	response, err := oc.OIDCService.Callback(request, cookieState, model.ClientInfo{
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	if err != nil {
+		if err == model.ErrorOIDCNotConfigured {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorInvalidOIDCState || err == model.ErrorOIDCEmailRequired {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusBadRequest,
+					Message: http.StatusText(http.StatusBadRequest),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorOIDCLoginFailed {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusUnauthorized,
+					Message: http.StatusText(http.StatusUnauthorized),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorEmailNotVerified || err == model.ErrorAccountDeleted {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusForbidden,
+					Message: http.StatusText(http.StatusForbidden),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorOIDCAccountExists || err == model.ErrorUsernameAlreadyExists {
+			ctx.AbortWithStatusJSON(http.StatusConflict, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusConflict,
+					Message: http.StatusText(http.StatusConflict),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
+
+// secureCookie keeps the state cookie off plain HTTP unless the callback
+// itself is plain HTTP, like with the local mock provider.
+func secureCookie() bool {
+	return !strings.HasPrefix(oidc.OIDC_REDIRECT_URL, "http://")
+}
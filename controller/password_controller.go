@@ -0,0 +1,284 @@
+package controller
+
+import (
+	"finalProject/model"
+	"finalProject/service"
+	"net/http"
+
+	Valid "github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordController struct {
+	PasswordService service.PasswordService
+}
+
+func NewPasswordController(passwordService service.PasswordService) *PasswordController {
+	return &PasswordController{
+		PasswordService: passwordService,
+	}
+}
+
+// ChangePassword godoc
+//
+//		@Summary			Change Password
+//		@Description		Change the password of the logged in user. All devices are logged out afterwards
+//		@Tags				User
+//		@Accept				json
+//		@Produce			json
+//		@Param				request body			model.UserChangePasswordRequest	true	"password policy: at least 8 characters with 3 of lowercase, uppercase, digits and symbols, not containing the username or email and not found in known breaches"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FieldFailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			403		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/me/password	[put]
+func (pc *PasswordController) ChangePassword(ctx *gin.Context) {
+	var request model.UserChangePasswordRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	valid, err := Valid.ValidateStruct(request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if !valid {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.GetString("user_id")
+
+	err = pc.PasswordService.ChangePassword(request, userID)
+	if err != nil {
+		if policy, ok := err.(model.PasswordPolicyError); ok {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FieldFailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusBadRequest,
+					Message: http.StatusText(http.StatusBadRequest),
+				},
+				Error:  err.Error(),
+				Fields: policy.Fields,
+			})
+			return
+		} else if err == model.ErrorWrongPassword {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusForbidden,
+					Message: http.StatusText(http.StatusForbidden),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Change password success, please login again",
+	})
+}
+
+// ForgotPassword godoc
+//
+//		@Summary			Forgot Password
+//		@Description		Send a password reset token to the email address if it belongs to an account
+//		@Tags				User
+//		@Accept				json
+//		@Produce			json
+//		@Param				request body			model.UserForgotPasswordRequest	true	"Registered email"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//	 @Router				/mygram/user/password/forgot	[post]
+func (pc *PasswordController) ForgotPassword(ctx *gin.Context) {
+	var request model.UserForgotPasswordRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	valid, err := Valid.ValidateStruct(request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if !valid {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	err = pc.PasswordService.ForgotPassword(request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "If the email is registered, a reset token has been sent",
+	})
+}
+
+// ResetPassword godoc
+//
+//		@Summary			Reset Password
+//		@Description		Set a new password with the token from the reset email. The token can only be used once
+//		@Tags				User
+//		@Accept				json
+//		@Produce			json
+//		@Param				request body			model.UserResetPasswordRequest	true	"password policy: at least 8 characters with 3 of lowercase, uppercase, digits and symbols, not containing the username or email and not found in known breaches"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FieldFailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//	 @Router				/mygram/user/password/reset	[post]
+func (pc *PasswordController) ResetPassword(ctx *gin.Context) {
+	var request model.UserResetPasswordRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	valid, err := Valid.ValidateStruct(request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if !valid {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	err = pc.PasswordService.ResetPassword(request)
+	if err != nil {
+		if policy, ok := err.(model.PasswordPolicyError); ok {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FieldFailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusBadRequest,
+					Message: http.StatusText(http.StatusBadRequest),
+				},
+				Error:  err.Error(),
+				Fields: policy.Fields,
+			})
+			return
+		} else if err == model.ErrorInvalidResetToken {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusBadRequest,
+					Message: http.StatusText(http.StatusBadRequest),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Reset password success, please login again",
+	})
+}
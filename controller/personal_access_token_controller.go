@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"finalProject/model"
+	"finalProject/service"
+	"net/http"
+
+	Valid "github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+)
+
+type PersonalAccessTokenController struct {
+	PersonalAccessTokenService service.PersonalAccessTokenService
+}
+
+func NewPersonalAccessTokenController(personalAccessTokenService service.PersonalAccessTokenService) *PersonalAccessTokenController {
+	return &PersonalAccessTokenController{
+		PersonalAccessTokenService: personalAccessTokenService,
+	}
+}
+
+// CreateToken godoc
+//
+//		@Summary			Create Personal Access Token
+//		@Description		Create a named token for scripts and integrations. Send it as bearer token like an access token. The token is only shown in this response || scopes: photos:read, photos:write, comments:read, comments:write, social_media:read, social_media:write || expires_in_days 0 means the token does not expire, unless the server sets a maximum lifetime
+//		@Tags				User
+//		@Accept				json
+//		@Produce			json
+//		@Param				request body			model.PersonalAccessTokenCreateRequest	true	"token name, scopes and optional expiry"
+//		@Success			201		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FieldFailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/tokens	[post]
+func (pc *PersonalAccessTokenController) CreateToken(ctx *gin.Context) {
+	var request model.PersonalAccessTokenCreateRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	valid, err := Valid.ValidateStruct(request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if !valid {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.GetString("user_id")
+
+	response, err := pc.PersonalAccessTokenService.Create(request, userID)
+	if err != nil {
+		if err == model.ErrorInvalidScope {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FieldFailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusBadRequest,
+					Message: http.StatusText(http.StatusBadRequest),
+				},
+				Error:  err.Error(),
+				Fields: []model.FieldError{model.ErrorInvalidScope},
+			})
+			return
+		}
+		if err == model.ErrorTokenExpiryTooLong {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FieldFailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusBadRequest,
+					Message: http.StatusText(http.StatusBadRequest),
+				},
+				Error:  err.Error(),
+				Fields: []model.FieldError{model.ErrorTokenExpiryTooLong},
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusCreated,
+			Message: http.StatusText(http.StatusCreated),
+		},
+		Data: response,
+	})
+}
+
+// GetAllToken godoc
+//
+//		@Summary			List Personal Access Tokens
+//		@Description		Show the personal access tokens of the logged in user that were not revoked
+//		@Tags				User
+//		@Produce			json
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/tokens	[get]
+func (pc *PersonalAccessTokenController) GetAllToken(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	response, err := pc.PersonalAccessTokenService.GetAll(userID)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
+
+// RevokeToken godoc
+//
+//		@Summary			Revoke Personal Access Token
+//		@Description		Revoke a personal access token, it stops working immediately
+//		@Tags				User
+//		@Produce			json
+//		@Param				token_id	path			string 	true		"Personal access token ID"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/tokens/{token_id}	[delete]
+func (pc *PersonalAccessTokenController) RevokeToken(ctx *gin.Context) {
+	tokenID := ctx.Param("token_id")
+	userID := ctx.GetString("user_id")
+
+	err := pc.PersonalAccessTokenService.Revoke(tokenID, userID)
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Token revoked",
+	})
+}
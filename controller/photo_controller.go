@@ -118,7 +118,7 @@ func (pc *PhotoController) CreatePhoto(ctx *gin.Context) {
 //		@Security			Bearer
 //	 @Router				/mygram/photos/get/all	[get]
 func (pc *PhotoController) GetAllPhoto(ctx *gin.Context) {
-	resp, err := pc.photoService.GetAllPhoto()
+	resp, err := pc.photoService.GetAllPhoto(ctx.GetString("user_id"))
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
 			Meta: model.Meta{
@@ -150,6 +150,7 @@ func (pc *PhotoController) GetAllPhoto(ctx *gin.Context) {
 //		@Param				photo_id	path			string 	true		"photo_id"
 //		@Success			201		{object}	model.SuccessResponse
 //		@Failure			401		{object}	model.FailedResponse
+//		@Failure			403		{object}	model.FailedResponse
 //		@Failure			404		{object}	model.FailedResponse
 //		@Failure			500		{object}	model.FailedResponse
 //		@Security			Bearer
@@ -157,8 +158,18 @@ func (pc *PhotoController) GetAllPhoto(ctx *gin.Context) {
 func (pc *PhotoController) GetOnePhoto(ctx *gin.Context) {
 	photoID := ctx.Param("photo_id")
 
-	response, err := pc.photoService.GetOnePhoto(photoID)
+	response, err := pc.photoService.GetOnePhoto(photoID, ctx.GetString("user_id"))
 	if err != nil {
+		if err == model.ErrorPrivateAccount {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusForbidden,
+					Message: http.StatusText(http.StatusForbidden),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
 			Meta: model.Meta{
 				Code:    http.StatusBadRequest,
@@ -182,7 +193,7 @@ func (pc *PhotoController) GetOnePhoto(ctx *gin.Context) {
 // UpdatePhoto godoc
 //
 //		@Summary			Update Photo
-//		@Description		Update single Photo Title and URL by input Social Media ID
+//		@Description		Update single Photo Title and URL by input Social Media ID. Moderators and admins can update any photo
 //		@Tags				Photo
 //		@Accept				json
 //		@Produce			json
@@ -246,7 +257,7 @@ func (pc *PhotoController) PhotoUpdate(ctx *gin.Context) {
 		return
 	}
 
-	response, err := pc.photoService.UpdatePhoto(request, userID.(string), photoID)
+	response, err := pc.photoService.UpdatePhoto(request, userID.(string), ctx.GetString("role"), photoID)
 	if err != nil {
 		if err == model.ErrorNotFound {
 			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
@@ -291,7 +302,7 @@ func (pc *PhotoController) PhotoUpdate(ctx *gin.Context) {
 // DeletePhoto godoc
 //
 //		@Summary			Delete Photo
-//		@Description		Delete Photo by input Social Media ID
+//		@Description		Delete Photo by input Social Media ID. The photo and its comments can be restored from the trash for 30 days. Moderators and admins can delete any photo, only a moderator can restore it then
 //		@Tags				Photo
 //		@Accept				json
 //		@Produce			json
@@ -318,7 +329,7 @@ func (pc *PhotoController) DeletePhoto(ctx *gin.Context) {
 		return
 	}
 
-	err := pc.photoService.DeletePhoto(photoID, userID.(string))
+	err := pc.photoService.DeletePhoto(photoID, userID.(string), ctx.GetString("role"))
 	if err != nil {
 		if err == model.ErrorNotFound {
 			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
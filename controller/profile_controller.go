@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"finalProject/model"
+	"finalProject/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileController struct {
+	ProfileService service.ProfileService
+}
+
+func NewProfileController(profileService service.ProfileService) *ProfileController {
+	return &ProfileController{
+		ProfileService: profileService,
+	}
+}
+
+// GetProfile godoc
+//
+//		@Summary			Get User Profile
+//		@Description		Show the public profile of a user with join date, photo and comment counts, social media and the latest photos
+//		@Tags				User
+//		@Produce			json
+//		@Param				username	path			string 	true		"Username"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			403		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/users/{username}	[get]
+func (pc *ProfileController) GetProfile(ctx *gin.Context) {
+	username := ctx.Param("username")
+	userID := ctx.GetString("user_id")
+
+	response, err := pc.ProfileService.GetProfile(username, userID)
+	if err != nil {
+		if err == model.ErrorPrivateAccount {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusForbidden,
+					Message: http.StatusText(http.StatusForbidden),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"finalProject/model"
+	"finalProject/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionController struct {
+	SessionService service.SessionService
+}
+
+func NewSessionController(sessionService service.SessionService) *SessionController {
+	return &SessionController{
+		SessionService: sessionService,
+	}
+}
+
+// GetAllSession godoc
+//
+//		@Summary			List Sessions
+//		@Description		Show the devices the logged in user is signed in on with user agent, IP, login time and last activity. current marks the session of the token in use
+//		@Tags				User
+//		@Produce			json
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/sessions	[get]
+func (sc *SessionController) GetAllSession(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	sessionID := ctx.GetString("session_id")
+
+	response, err := sc.SessionService.GetAll(userID, sessionID)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
+
+// RevokeSession godoc
+//
+//		@Summary			Revoke Session
+//		@Description		Sign out one device. Its access token and refresh token stop working immediately
+//		@Tags				User
+//		@Produce			json
+//		@Param				session_id	path			string 	true		"Session ID"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/sessions/{session_id}	[delete]
+func (sc *SessionController) RevokeSession(ctx *gin.Context) {
+	sessionID := ctx.Param("session_id")
+	userID := ctx.GetString("user_id")
+
+	err := sc.SessionService.Revoke(sessionID, userID)
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Session revoked",
+	})
+}
@@ -120,7 +120,7 @@ func (sc *SocialMediaController) CreateSocialMedia(ctx *gin.Context) {
 //		@Security			Bearer
 //	 @Router				/mygram/social_media/get/all	[get]
 func (sc *SocialMediaController) GetAllSocialMedia(ctx *gin.Context) {
-	AllSocMed, err := sc.SocialMediaService.GetAll()
+	AllSocMed, err := sc.SocialMediaService.GetAll(ctx.GetString("user_id"))
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
 			Meta: model.Meta{
@@ -152,16 +152,26 @@ func (sc *SocialMediaController) GetAllSocialMedia(ctx *gin.Context) {
 //		@Param				social_id	path			string 	true		"insert Social Media ID"
 //		@Success			201		{object}	model.SuccessResponse
 //		@Failure			401		{object}	model.FailedResponse
+//		@Failure			403		{object}	model.FailedResponse
 //		@Failure			404		{object}	model.FailedResponse
 //		@Failure			500		{object}	model.FailedResponse
 //		@Security			Bearer
 //	 @Router				/mygram/social_media/get/{social_id}	[get]
 func (sc *SocialMediaController) GetOneSocial(ctx *gin.Context) {
 	SocialId := ctx.Param("social_id")
-	socMed, err := sc.SocialMediaService.GetOne(SocialId)
+	socMed, err := sc.SocialMediaService.GetOne(SocialId, ctx.GetString("user_id"))
 
 	if err != nil {
-		if err == model.ErrorNotFound {
+		if err == model.ErrorPrivateAccount {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusForbidden,
+					Message: http.StatusText(http.StatusForbidden),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorNotFound {
 			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
 				Meta: model.Meta{
 					Code:    http.StatusNotFound,
@@ -192,7 +202,7 @@ func (sc *SocialMediaController) GetOneSocial(ctx *gin.Context) {
 // UpdateSocialMedia godoc
 //
 //		@Summary			Update Social Media Account
-//		@Description		Update single Social Media Account by input Social Media ID
+//		@Description		Update single Social Media Account by input Social Media ID. Moderators and admins can update any account
 //		@Tags				Social Media
 //		@Accept				json
 //		@Produce			json
@@ -255,7 +265,7 @@ func (sc *SocialMediaController) UpdateSocialMedia(ctx *gin.Context) {
 		return
 	}
 
-	res, err := sc.SocialMediaService.Update(UpSocial, SocialId, userId.(string))
+	res, err := sc.SocialMediaService.Update(UpSocial, SocialId, userId.(string), ctx.GetString("role"))
 
 	if err != nil {
 		if err == model.ErrorNotFound {
@@ -300,7 +310,7 @@ func (sc *SocialMediaController) UpdateSocialMedia(ctx *gin.Context) {
 // DeleteSocialMedia godoc
 //
 //		@Summary			Delete Social Media Account
-//		@Description		Delete single Social Media Account by input Social Media ID
+//		@Description		Delete single Social Media Account by input Social Media ID. The account can be restored from the trash for 30 days. Moderators and admins can delete any account, only a moderator can restore it then
 //		@Tags				Social Media
 //		@Accept				json
 //		@Produce			json
@@ -327,7 +337,7 @@ func (sc *SocialMediaController) DeleteSocial(ctx *gin.Context) {
 		return
 	}
 
-	err := sc.SocialMediaService.Delete(SocialId, UserID.(string))
+	err := sc.SocialMediaService.Delete(SocialId, UserID.(string), ctx.GetString("role"))
 	if err != nil {
 		if err == model.ErrorNotFound {
 			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
@@ -0,0 +1,246 @@
+package controller
+
+import (
+	"finalProject/model"
+	"finalProject/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashController struct {
+	TrashService service.TrashService
+}
+
+func NewTrashController(trashService service.TrashService) *TrashController {
+	return &TrashController{
+		TrashService: trashService,
+	}
+}
+
+// GetTrash godoc
+//
+//		@Summary			Get Trash
+//		@Description		List the photos, comments and social media the logged in user deleted in the last 30 days, with when each is removed for good
+//		@Tags				User
+//		@Produce			json
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/trash	[get]
+func (tc *TrashController) GetTrash(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	response, err := tc.TrashService.GetTrash(userID)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
+
+// RestorePhoto godoc
+//
+//		@Summary			Restore Photo
+//		@Description		Restore a photo from the trash together with the comments deleted along with it. Owners can restore what they deleted themselves, moderators also what a moderator removed
+//		@Tags				User
+//		@Produce			json
+//		@Param				photo_id	path			string 	true		"Photo ID"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/trash/photos/{photo_id}/restore	[post]
+func (tc *TrashController) RestorePhoto(ctx *gin.Context) {
+	photoID := ctx.Param("photo_id")
+	userID := ctx.GetString("user_id")
+
+	err := tc.TrashService.RestorePhoto(photoID, userID, ctx.GetString("role"))
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Restore photo success",
+	})
+}
+
+// RestoreComment godoc
+//
+//		@Summary			Restore Comment
+//		@Description		Restore a comment from the trash. A comment on a deleted photo comes back with the photo. Owners can restore what they deleted themselves, moderators also what a moderator removed
+//		@Tags				User
+//		@Produce			json
+//		@Param				comment_id	path			string 	true		"Comment ID"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/trash/comments/{comment_id}/restore	[post]
+func (tc *TrashController) RestoreComment(ctx *gin.Context) {
+	commentID := ctx.Param("comment_id")
+	userID := ctx.GetString("user_id")
+
+	err := tc.TrashService.RestoreComment(commentID, userID, ctx.GetString("role"))
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Restore comment success",
+	})
+}
+
+// RestoreSocialMedia godoc
+//
+//		@Summary			Restore Social Media
+//		@Description		Restore a social media account from the trash. Owners can restore what they deleted themselves, moderators also what a moderator removed
+//		@Tags				User
+//		@Produce			json
+//		@Param				social_id	path			string 	true		"Social Media ID"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/trash/social_media/{social_id}/restore	[post]
+func (tc *TrashController) RestoreSocialMedia(ctx *gin.Context) {
+	socialID := ctx.Param("social_id")
+	userID := ctx.GetString("user_id")
+
+	err := tc.TrashService.RestoreSocialMedia(socialID, userID, ctx.GetString("role"))
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Restore social media success",
+	})
+}
+
+// RestoreUser godoc
+//
+//		@Summary			Restore User
+//		@Description		Restore an account deleted in the last 30 days together with the content deleted along with it. Admin only
+//		@Tags				Admin
+//		@Produce			json
+//		@Param				user_id	path			string 	true		"User ID"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			403		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/admin/users/{user_id}/restore	[post]
+func (tc *TrashController) RestoreUser(ctx *gin.Context) {
+	userID := ctx.Param("user_id")
+
+	err := tc.TrashService.RestoreUser(userID)
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Restore user success",
+	})
+}
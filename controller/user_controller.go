@@ -4,6 +4,7 @@ import (
 	"finalProject/model"
 	"finalProject/service"
 	"net/http"
+	"strconv"
 
 	Valid "github.com/asaskevich/govalidator"
 	"github.com/gin-gonic/gin"
@@ -26,9 +27,10 @@ func NewUserController(UserService service.UserService) *UserController {
 //		@Tags				User
 //		@Accept				json
 //		@Produce			json
-//		@Param				request body			model.UserRegisterRequest	true	"minimum age to register is 8 years old. || password minimum is 6 character."
+//		@Param				request body			model.UserRegisterRequest	true	"minimum age to register is 8 years old. || password policy: at least 8 characters with 3 of lowercase, uppercase, digits and symbols, not containing the username or email and not found in known breaches"
 //		@Success			201		{object}		model.SuccessResponse
-//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			400		{object}		model.FieldFailedResponse
+//		@Failure			409		{object}		model.FieldFailedResponse
 //		@Failure			500		{object}		model.FailedResponse
 //	 @Router				/mygram/user/register	[post]
 func (uc *UserController) Register(ctx *gin.Context) {
@@ -70,6 +72,27 @@ func (uc *UserController) Register(ctx *gin.Context) {
 
 	response, err := uc.UserService.Register(request)
 	if err != nil {
+		if policy, ok := err.(model.PasswordPolicyError); ok {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FieldFailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusBadRequest,
+					Message: http.StatusText(http.StatusBadRequest),
+				},
+				Error:  err.Error(),
+				Fields: policy.Fields,
+			})
+			return
+		} else if err == model.ErrorEmailAlreadyExists || err == model.ErrorUsernameAlreadyExists {
+			ctx.AbortWithStatusJSON(http.StatusConflict, model.FieldFailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusConflict,
+					Message: http.StatusText(http.StatusConflict),
+				},
+				Error:  err.Error(),
+				Fields: []model.FieldError{err.(model.FieldError)},
+			})
+			return
+		}
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
 			Meta: model.Meta{
 				Code:    http.StatusInternalServerError,
@@ -94,13 +117,16 @@ func (uc *UserController) Register(ctx *gin.Context) {
 // Register godoc
 //
 //		@Summary			Login User
-//		@Description		Sign in MyGram User to access all feature. NOTE : to input access token to Authorize button, please write with format: bearer YourTokenAccess || Token will be expired in 1 hours
+//		@Description		Sign in MyGram User to access all feature. NOTE : to input access token to Authorize button, please write with format: bearer YourTokenAccess || Token will be expired in 1 hours, use the refresh token to get a new one || Accounts with two-factor authentication get a challenge_token instead, finish the login with /mygram/user/login/2fa
 //		@Tags				User
 //		@Accept				json
 //		@Produce			json
-//		@Param				request body			model.UserLoginRequest	true	"User Login Request is required"
+//		@Param				request body			model.UserLoginRequest	true	"identifier can be the email or the username"
 //		@Success			201		{object}		model.SuccessResponse
 //		@Failure			400		{object}		model.FailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			403		{object}		model.FailedResponse
+//		@Failure			429		{object}		model.FailedResponse
 //		@Failure			500		{object}		model.FailedResponse
 //	 @Router				/mygram/user/login	[post]
 func (uc *UserController) Login(ctx *gin.Context) {
@@ -139,9 +165,22 @@ func (uc *UserController) Login(ctx *gin.Context) {
 		return
 	}
 
-	response, err := uc.UserService.Login(request)
+	response, err := uc.UserService.Login(request, model.ClientInfo{
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
 	if err != nil {
-		if err == model.ErrorInvalidEmailOrPassword {
+		if tooMany, ok := err.(model.TooManyAttemptsError); ok {
+			ctx.Header("Retry-After", strconv.Itoa(tooMany.RetryAfterSeconds()))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusTooManyRequests,
+					Message: http.StatusText(http.StatusTooManyRequests),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorInvalidEmailOrPassword {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, model.FailedResponse{
 				Meta: model.Meta{
 					Code:    http.StatusUnauthorized,
@@ -150,6 +189,15 @@ func (uc *UserController) Login(ctx *gin.Context) {
 				Error: err.Error(),
 			})
 			return
+		} else if err == model.ErrorEmailNotVerified {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusForbidden,
+					Message: http.StatusText(http.StatusForbidden),
+				},
+				Error: err.Error(),
+			})
+			return
 		} else if err == model.ErrorInvalidToken {
 			ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
 				Meta: model.Meta{
@@ -178,3 +226,568 @@ func (uc *UserController) Login(ctx *gin.Context) {
 		Data: response,
 	})
 }
+
+// LoginTwoFactor godoc
+//
+//		@Summary			Login User Two-Factor
+//		@Description		Finish a login on an account with two-factor authentication. The challenge token from /mygram/user/login expires in 5 minutes
+//		@Tags				User
+//		@Accept				json
+//		@Produce			json
+//		@Param				request body			model.UserLoginTwoFactorRequest	true	"code is the 6 digit code from the authenticator app or a recovery code"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			429		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//	 @Router				/mygram/user/login/2fa	[post]
+func (uc *UserController) LoginTwoFactor(ctx *gin.Context) {
+	var request model.UserLoginTwoFactorRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	valid, err := Valid.ValidateStruct(request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if !valid {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	response, err := uc.UserService.LoginTwoFactor(request, model.ClientInfo{
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	if err != nil {
+		if tooMany, ok := err.(model.TooManyAttemptsError); ok {
+			ctx.Header("Retry-After", strconv.Itoa(tooMany.RetryAfterSeconds()))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusTooManyRequests,
+					Message: http.StatusText(http.StatusTooManyRequests),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorInvalidChallengeToken || err == model.ErrorInvalidTwoFactorCode {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusUnauthorized,
+					Message: http.StatusText(http.StatusUnauthorized),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
+
+// Refresh godoc
+//
+//		@Summary			Refresh Access Token
+//		@Description		Exchange a refresh token for a new access token and refresh token. Each refresh token can only be used once, reusing an old one will sign out that login
+//		@Tags				User
+//		@Accept				json
+//		@Produce			json
+//		@Param				request body			model.UserRefreshRequest	true	"Refresh token from login"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//	 @Router				/mygram/user/refresh	[post]
+func (uc *UserController) Refresh(ctx *gin.Context) {
+	var request model.UserRefreshRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	valid, err := Valid.ValidateStruct(request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if !valid {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	response, err := uc.UserService.Refresh(request, model.ClientInfo{
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	if err != nil {
+		if err == model.ErrorInvalidRefreshToken || err == model.ErrorRefreshTokenReused {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusUnauthorized,
+					Message: http.StatusText(http.StatusUnauthorized),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
+
+// Logout godoc
+//
+//		@Summary			Logout
+//		@Description		End the current session, revoking the access token in use and the refresh token of the same login
+//		@Tags				User
+//		@Produce			json
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/logout	[post]
+func (uc *UserController) Logout(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	jti := ctx.GetString("jti")
+	sessionID := ctx.GetString("session_id")
+	expiresAt := ctx.GetTime("token_expires_at")
+
+	err := uc.UserService.Logout(userID, jti, sessionID, expiresAt)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Logout success",
+	})
+}
+
+// LogoutAll godoc
+//
+//		@Summary			Logout Everywhere
+//		@Description		Revoke every access token and refresh token of the current user on all devices
+//		@Tags				User
+//		@Produce			json
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/logout/all	[post]
+func (uc *UserController) LogoutAll(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	err := uc.UserService.LogoutAll(userID)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Logout from all devices success",
+	})
+}
+
+// GetMe godoc
+//
+//		@Summary			Get My Account
+//		@Description		Show the account of the logged in user
+//		@Tags				User
+//		@Produce			json
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/me	[get]
+func (uc *UserController) GetMe(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	response, err := uc.UserService.GetMe(userID)
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
+
+// UpdateMe godoc
+//
+//		@Summary			Update My Account
+//		@Description		Change username, email and age of the logged in user. minimum age is 8 years old. The age counts up from the day it is set. Lowering it below the restricted account age makes the account private. An age set by an admin cannot be changed
+//		@Tags				User
+//		@Accept				json
+//		@Produce			json
+//		@Param				request body			model.UserUpdateRequest	true	"User Update Request is required"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			403		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			409		{object}		model.FieldFailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/me	[put]
+func (uc *UserController) UpdateMe(ctx *gin.Context) {
+	var request model.UserUpdateRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	valid, err := Valid.ValidateStruct(request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if !valid {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.GetString("user_id")
+
+	response, err := uc.UserService.UpdateMe(request, userID)
+	if err != nil {
+		if err == model.ErrorAgeLocked {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusForbidden,
+					Message: http.StatusText(http.StatusForbidden),
+				},
+				Error: err.Error(),
+			})
+			return
+		} else if err == model.ErrorEmailAlreadyExists || err == model.ErrorUsernameAlreadyExists {
+			ctx.AbortWithStatusJSON(http.StatusConflict, model.FieldFailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusConflict,
+					Message: http.StatusText(http.StatusConflict),
+				},
+				Error:  err.Error(),
+				Fields: []model.FieldError{err.(model.FieldError)},
+			})
+			return
+		} else if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: response,
+	})
+}
+
+// DeleteMe godoc
+//
+//		@Summary			Delete My Account
+//		@Description		Delete the logged in user together with all of their photos, comments and social media. An admin can restore the account for 30 days
+//		@Tags				User
+//		@Produce			json
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			401		{object}		model.FailedResponse
+//		@Failure			404		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//		@Security			Bearer
+//	 @Router				/mygram/user/me	[delete]
+func (uc *UserController) DeleteMe(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+
+	err := uc.UserService.DeleteMe(userID)
+	if err != nil {
+		if err == model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusNotFound,
+					Message: http.StatusText(http.StatusNotFound),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Delete account success",
+	})
+}
+
+// VerifyEmail godoc
+//
+//		@Summary			Verify Email
+//		@Description		Confirm the email address with the token from the verification email
+//		@Tags				User
+//		@Produce			json
+//		@Param				token	query			string 	true		"verification token"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//	 @Router				/mygram/user/verify	[get]
+func (uc *UserController) VerifyEmail(ctx *gin.Context) {
+	token := ctx.Query("token")
+
+	err := uc.UserService.VerifyEmail(token)
+	if err != nil {
+		if err == model.ErrorInvalidVerificationToken {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusBadRequest,
+					Message: http.StatusText(http.StatusBadRequest),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "Email verified",
+	})
+}
+
+// ResendVerification godoc
+//
+//		@Summary			Resend Verification Email
+//		@Description		Send a new verification link if the email belongs to an unverified account
+//		@Tags				User
+//		@Accept				json
+//		@Produce			json
+//		@Param				request body			model.UserResendVerificationRequest	true	"Registered email"
+//		@Success			200		{object}		model.SuccessResponse
+//		@Failure			400		{object}		model.FailedResponse
+//		@Failure			500		{object}		model.FailedResponse
+//	 @Router				/mygram/user/verify/resend	[post]
+func (uc *UserController) ResendVerification(ctx *gin.Context) {
+	var request model.UserResendVerificationRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	valid, err := Valid.ValidateStruct(request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if !valid {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusBadRequest,
+				Message: http.StatusText(http.StatusBadRequest),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	err = uc.UserService.ResendVerification(request)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.SuccessResponse{
+		Meta: model.Meta{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+		Data: "If the email belongs to an unverified account, a new verification link has been sent",
+	})
+}
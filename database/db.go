@@ -31,7 +31,7 @@ func StartDB() {
 		panic(err)
 	}
 
-	db.Debug().AutoMigrate(model.User{}, model.SocialMedia{}, model.Photo{}, model.Comment{})
+	db.Debug().AutoMigrate(model.User{}, model.SocialMedia{}, model.Photo{}, model.Comment{}, model.RefreshToken{}, model.RevokedToken{}, model.UserRevocation{}, model.PasswordReset{}, model.LoginLockout{}, model.RecoveryCode{}, model.PersonalAccessToken{}, model.UserIdentity{}, model.OIDCLoginState{}, model.Session{}, model.Follow{}, model.Block{}, model.Mute{}, model.FeedItem{}, model.FeedPullAuthor{}, model.FollowRequest{}, model.DataExport{})
 
 }
 func GetDB() *gorm.DB {
@@ -1,27 +1,33 @@
 package helper
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"time"
 
 	"github.com/golang-jwt/jwt"
 	// "github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 
 	"finalProject/model"
 )
 
-func GenerateID() string {
-	return uuid.New().String()
-}
+const (
+	AccessTokenDuration       = 1 * time.Hour
+	RefreshTokenDuration      = 30 * 24 * time.Hour
+	PasswordResetDuration     = 30 * time.Minute
+	EmailVerificationDuration = 24 * time.Hour
+)
 
-func Hash(plain string) (string, error) {
-	result, err := bcrypt.GenerateFromPassword([]byte(plain), 8)
-	if err != nil {
-		return "", err
-	}
+// AccessTokenType is the typ claim of access tokens. The same keys sign
+// single purpose tokens, so services verifying against the JWKS must
+// require it as well.
+const AccessTokenType = "access"
 
-	return string(result), nil
+func GenerateID() string {
+	return uuid.New().String()
 }
 
 func GenerateTime() string {
@@ -29,39 +35,113 @@ func GenerateTime() string {
 	return t.Format(time.RFC3339)
 }
 
-func IsHashValid(hash string, plain string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain))
-
-	return err == nil
-}
-
-func GenerateAccessToken(userID string, email string) (string, error) {
+func GenerateAccessToken(userID string, email string, role string, sessionID string) (string, error) {
+	now := time.Now()
 	claims := jwt.MapClaims{
+		"typ":     AccessTokenType,
+		"jti":     GenerateID(),
+		"sid":     sessionID,
 		"email":   email,
 		"user_id": userID,
-		"exp":     time.Now().Add(1 * time.Hour).Unix(),
+		"role":    role,
+		"iat":     now.Unix(),
+		"exp":     now.Add(AccessTokenDuration).Unix(),
 	}
 
-	return generateToken(claims, "4mAn___80SS")
+	return generateToken(claims)
 }
 
-func generateToken(claims jwt.MapClaims, secret string) (string, error) {
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+func generateToken(claims jwt.MapClaims) (string, error) {
+	key, err := keyRing.Active()
+	if err != nil {
+		return "", err
+	}
 
-	tokenString, err := jwtToken.SignedString([]byte(secret))
+	jwtToken := jwt.NewWithClaims(key.Method, claims)
+	jwtToken.Header["kid"] = key.ID
+
+	tokenString, err := jwtToken.SignedString(key.SignKey)
 	return tokenString, err
 }
 
 func VerifyAccessToken(token string) (*jwt.Token, error) {
+	jwtToken, err := parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := jwtToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, model.ErrorInvalidToken
+	}
+
+	// tokens minted for a single purpose, like email links, are never access tokens
+	if claims["typ"] != AccessTokenType {
+		return nil, model.ErrorInvalidToken
+	}
+	if _, ok := claims["purpose"]; ok {
+		return nil, model.ErrorInvalidToken
+	}
 
-	jwtToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
-		_, ok := t.Method.(*jwt.SigningMethodHMAC)
+	return jwtToken, nil
+}
+
+// GeneratePurposeToken signs a short lived token that is only accepted by
+// VerifyPurposeToken for the same purpose.
+func GeneratePurposeToken(purpose string, claims jwt.MapClaims, duration time.Duration) (string, error) {
+	tokenClaims := jwt.MapClaims{}
+	for k, v := range claims {
+		tokenClaims[k] = v
+	}
+	tokenClaims["typ"] = purpose
+	tokenClaims["purpose"] = purpose
+	tokenClaims["exp"] = time.Now().Add(duration).Unix()
+
+	return generateToken(tokenClaims)
+}
+
+func VerifyPurposeToken(token string, purpose string) (jwt.MapClaims, error) {
+	jwtToken, err := parseToken(token)
+	if err != nil {
+		return nil, model.ErrorInvalidToken
+	}
+
+	claims, ok := jwtToken.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != purpose {
+		return nil, model.ErrorInvalidToken
+	}
+
+	return claims, nil
+}
+
+func parseToken(token string) (*jwt.Token, error) {
+	return jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := keyRing.Lookup(kid)
 		if !ok {
 			return nil, model.ErrorInvalidToken
 		}
 
-		return []byte("4mAn___80SS"), nil
+		if t.Method.Alg() != key.Method.Alg() {
+			return nil, model.ErrorInvalidToken
+		}
+
+		return key.VerifyKey, nil
 	})
+}
+
+// GenerateRandomToken returns an opaque URL-safe token for refresh tokens and
+// other secrets that are only ever stored hashed.
+func GenerateRandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
 
-	return jwtToken, err
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
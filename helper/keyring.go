@@ -0,0 +1,332 @@
+package helper
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"finalProject/model"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// Key ring configuration:
+//
+//	JWT_KEYS_DIR      directory holding one key per file, the file name without extension is the kid.
+//	                  A file is either an HMAC secret or a PEM encoded RSA / Ed25519 private key.
+//	JWT_SECRET        single secret, registered under JWT_SECRET_KID (default "default")
+//	JWT_ACTIVE_KID    kid used to sign new tokens, optional when only one key is configured
+//	JWT_RETIRED_KIDS  comma separated kid=RFC3339 pairs, the key stops verifying after that time
+//
+// To rotate, add the new key, point JWT_ACTIVE_KID at it and retire the old kid
+// at least one access token lifetime in the future.
+var (
+	JWT_KEYS_DIR     = os.Getenv("JWT_KEYS_DIR")
+	JWT_SECRET       = os.Getenv("JWT_SECRET")
+	JWT_SECRET_KID   = os.Getenv("JWT_SECRET_KID")
+	JWT_ACTIVE_KID   = os.Getenv("JWT_ACTIVE_KID")
+	JWT_RETIRED_KIDS = os.Getenv("JWT_RETIRED_KIDS")
+
+	keyRing = NewKeyRing()
+)
+
+const (
+	minSecretLength = 32
+	minRSABits      = 2048
+)
+
+type SigningKey struct {
+	ID        string
+	Method    jwt.SigningMethod
+	SignKey   interface{}
+	VerifyKey interface{}
+	RetireAt  time.Time
+}
+
+func (k SigningKey) retired(now time.Time) bool {
+	return !k.RetireAt.IsZero() && !now.Before(k.RetireAt)
+}
+
+type KeyRing struct {
+	mu     sync.RWMutex
+	keys   map[string]SigningKey
+	active string
+}
+
+func NewKeyRing() *KeyRing {
+	return &KeyRing{
+		keys: map[string]SigningKey{},
+	}
+}
+
+func (kr *KeyRing) Add(key SigningKey) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	if key.ID == "" {
+		return fmt.Errorf("signing key without kid")
+	}
+	if _, ok := kr.keys[key.ID]; ok {
+		return fmt.Errorf("duplicate signing key %q", key.ID)
+	}
+
+	kr.keys[key.ID] = key
+	return nil
+}
+
+// Retire keeps the key valid for verification until at, and never signs with it again.
+func (kr *KeyRing) Retire(kid string, at time.Time) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	key, ok := kr.keys[kid]
+	if !ok {
+		return fmt.Errorf("unknown signing key %q", kid)
+	}
+	if kr.active == kid {
+		return fmt.Errorf("signing key %q is active and cannot be retired", kid)
+	}
+
+	key.RetireAt = at
+	kr.keys[kid] = key
+	return nil
+}
+
+func (kr *KeyRing) SetActive(kid string) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	key, ok := kr.keys[kid]
+	if !ok {
+		return fmt.Errorf("unknown signing key %q", kid)
+	}
+	if !key.RetireAt.IsZero() {
+		return fmt.Errorf("signing key %q is retired", kid)
+	}
+
+	kr.active = kid
+	return nil
+}
+
+func (kr *KeyRing) Active() (SigningKey, error) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	key, ok := kr.keys[kr.active]
+	if !ok {
+		return SigningKey{}, fmt.Errorf("no active signing key")
+	}
+	return key, nil
+}
+
+// Lookup returns a key that may still verify tokens.
+func (kr *KeyRing) Lookup(kid string) (SigningKey, bool) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	key, ok := kr.keys[kid]
+	if !ok || key.retired(time.Now()) {
+		return SigningKey{}, false
+	}
+	return key, true
+}
+
+func (kr *KeyRing) ids() []string {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	ids := make([]string, 0, len(kr.keys))
+	for id := range kr.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// LoadKeyRing builds the key ring used by the token helpers from the environment.
+func LoadKeyRing() error {
+	kr := NewKeyRing()
+
+	if JWT_KEYS_DIR != "" {
+		entries, err := os.ReadDir(JWT_KEYS_DIR)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+
+			raw, err := os.ReadFile(filepath.Join(JWT_KEYS_DIR, entry.Name()))
+			if err != nil {
+				return err
+			}
+
+			kid := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+			key, err := parseKey(kid, raw)
+			if err != nil {
+				return err
+			}
+			if err := kr.Add(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	if JWT_SECRET != "" {
+		kid := JWT_SECRET_KID
+		if kid == "" {
+			kid = "default"
+		}
+		key, err := hmacKey(kid, JWT_SECRET)
+		if err != nil {
+			return err
+		}
+		if err := kr.Add(key); err != nil {
+			return err
+		}
+	}
+
+	ids := kr.ids()
+	if len(ids) == 0 {
+		return fmt.Errorf("no JWT signing keys configured, set JWT_SECRET or JWT_KEYS_DIR")
+	}
+
+	for _, retired := range strings.Split(JWT_RETIRED_KIDS, ",") {
+		retired = strings.TrimSpace(retired)
+		if retired == "" {
+			continue
+		}
+
+		kid, at, found := strings.Cut(retired, "=")
+		if !found {
+			return fmt.Errorf("JWT_RETIRED_KIDS entry %q must be kid=RFC3339", retired)
+		}
+		retireAt, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			return fmt.Errorf("JWT_RETIRED_KIDS entry %q: %w", retired, err)
+		}
+		if err := kr.Retire(kid, retireAt); err != nil {
+			return err
+		}
+	}
+
+	active := JWT_ACTIVE_KID
+	if active == "" {
+		if len(ids) > 1 {
+			return fmt.Errorf("JWT_ACTIVE_KID is required when more than one signing key is configured")
+		}
+		active = ids[0]
+	}
+	if err := kr.SetActive(active); err != nil {
+		return err
+	}
+
+	keyRing = kr
+	return nil
+}
+
+func parseKey(kid string, raw []byte) (SigningKey, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return hmacKey(kid, strings.TrimSpace(string(raw)))
+	}
+
+	var (
+		privateKey interface{}
+		err        error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return SigningKey{}, fmt.Errorf("signing key %q: unsupported PEM block %q", kid, block.Type)
+	}
+	if err != nil {
+		return SigningKey{}, fmt.Errorf("signing key %q: %w", kid, err)
+	}
+
+	switch privateKey := privateKey.(type) {
+	case *rsa.PrivateKey:
+		if privateKey.N.BitLen() < minRSABits {
+			return SigningKey{}, fmt.Errorf("signing key %q: RSA keys must be at least %d bits", kid, minRSABits)
+		}
+		return SigningKey{
+			ID:        kid,
+			Method:    jwt.SigningMethodRS256,
+			SignKey:   privateKey,
+			VerifyKey: &privateKey.PublicKey,
+		}, nil
+	case ed25519.PrivateKey:
+		return SigningKey{
+			ID:        kid,
+			Method:    jwt.SigningMethodEdDSA,
+			SignKey:   privateKey,
+			VerifyKey: privateKey.Public().(ed25519.PublicKey),
+		}, nil
+	default:
+		return SigningKey{}, fmt.Errorf("signing key %q: unsupported key type %T", kid, privateKey)
+	}
+}
+
+func hmacKey(kid string, secret string) (SigningKey, error) {
+	if len(secret) < minSecretLength {
+		return SigningKey{}, fmt.Errorf("signing key %q must be at least %d characters", kid, minSecretLength)
+	}
+
+	return SigningKey{
+		ID:        kid,
+		Method:    jwt.SigningMethodHS256,
+		SignKey:   []byte(secret),
+		VerifyKey: []byte(secret),
+	}, nil
+}
+
+// PublicKeySet lists the public half of every asymmetric key that still
+// verifies tokens. HMAC secrets are never published.
+func PublicKeySet() model.JSONWebKeySet {
+	keySet := model.JSONWebKeySet{
+		Keys: []model.JSONWebKey{},
+	}
+
+	for _, kid := range keyRing.ids() {
+		key, ok := keyRing.Lookup(kid)
+		if !ok {
+			continue
+		}
+
+		switch publicKey := key.VerifyKey.(type) {
+		case *rsa.PublicKey:
+			keySet.Keys = append(keySet.Keys, model.JSONWebKey{
+				Kty: "RSA",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			keySet.Keys = append(keySet.Keys, model.JSONWebKey{
+				Kty: "OKP",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(publicKey),
+			})
+		}
+	}
+
+	return keySet
+}
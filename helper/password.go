@@ -0,0 +1,173 @@
+package helper
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Password hashing policy. New hashes use PASSWORD_HASH_ALGORITHM, "argon2id"
+// (default) or "bcrypt". Hashes made with another algorithm or other
+// parameters still verify and are replaced on the next login.
+//
+//	ARGON2_MEMORY_KIB  memory in KiB, defaults to 65536 (64 MiB)
+//	ARGON2_TIME        passes over the memory, defaults to 3
+//	ARGON2_THREADS     parallelism, defaults to 4
+//	BCRYPT_COST        defaults to 12
+var (
+	PASSWORD_HASH_ALGORITHM = os.Getenv("PASSWORD_HASH_ALGORITHM")
+
+	ARGON2_MEMORY_KIB = GetEnvInt("ARGON2_MEMORY_KIB", 64*1024)
+	ARGON2_TIME       = GetEnvInt("ARGON2_TIME", 3)
+	ARGON2_THREADS    = GetEnvInt("ARGON2_THREADS", 4)
+	BCRYPT_COST       = GetEnvInt("BCRYPT_COST", 12)
+)
+
+const (
+	HashAlgorithmArgon2id = "argon2id"
+	HashAlgorithmBcrypt   = "bcrypt"
+
+	argon2idPrefix  = "$argon2id$"
+	argon2SaltSize  = 16
+	argon2KeyLength = 32
+)
+
+type argon2Params struct {
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+}
+
+func currentArgon2Params() argon2Params {
+	return argon2Params{
+		Memory:  uint32(ARGON2_MEMORY_KIB),
+		Time:    uint32(ARGON2_TIME),
+		Threads: uint8(ARGON2_THREADS),
+	}
+}
+
+// Hash hashes a password with the configured algorithm.
+func Hash(plain string) (string, error) {
+	if PASSWORD_HASH_ALGORITHM == HashAlgorithmBcrypt {
+		result, err := bcrypt.GenerateFromPassword([]byte(plain), BCRYPT_COST)
+		if err != nil {
+			return "", err
+		}
+		return string(result), nil
+	}
+
+	salt := make([]byte, argon2SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	params := currentArgon2Params()
+	key := argon2.IDKey([]byte(plain), salt, params.Time, params.Memory, params.Threads, argon2KeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		params.Memory,
+		params.Time,
+		params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// IsHashValid checks a password against an argon2id or bcrypt hash. An empty
+// hash, like on accounts created through an external provider, never matches.
+func IsHashValid(hash string, plain string) bool {
+	if strings.HasPrefix(hash, argon2idPrefix) {
+		params, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return false
+		}
+
+		other := argon2.IDKey([]byte(plain), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, other) == 1
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain))
+
+	return err == nil
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+// DummyHash returns a hash of a random password made with the current policy.
+// Checking a password against it takes as long as against a real hash, so a
+// login for an unknown account is not answered faster.
+func DummyHash() string {
+	dummyHashOnce.Do(func() {
+		random := make([]byte, 32)
+		if _, err := rand.Read(random); err != nil {
+			return
+		}
+		dummyHash, _ = Hash(base64.RawStdEncoding.EncodeToString(random))
+	})
+	return dummyHash
+}
+
+// NeedsRehash reports whether a valid hash was made with another algorithm
+// or weaker parameters than the current policy.
+func NeedsRehash(hash string) bool {
+	if PASSWORD_HASH_ALGORITHM == HashAlgorithmBcrypt {
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost != BCRYPT_COST
+	}
+
+	params, _, key, err := decodeArgon2id(hash)
+	return err != nil || params != currentArgon2Params() || len(key) != argon2KeyLength
+}
+
+// decodeArgon2id parses the PHC string format,
+// $argon2id$v=19$m=65536,t=3,p=4$salt$key with unpadded base64.
+func decodeArgon2id(hash string) (argon2Params, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != HashAlgorithmArgon2id {
+		return argon2Params{}, nil, nil, fmt.Errorf("not an argon2id hash")
+	}
+
+	var version int
+	_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil {
+		return argon2Params{}, nil, nil, err
+	}
+	if version != argon2.Version {
+		return argon2Params{}, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	var params argon2Params
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads)
+	if err != nil {
+		return argon2Params{}, nil, nil, err
+	}
+	if params.Memory == 0 || params.Time == 0 || params.Threads == 0 {
+		return argon2Params{}, nil, nil, fmt.Errorf("invalid argon2 parameters")
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return argon2Params{}, nil, nil, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return argon2Params{}, nil, nil, err
+	}
+	if len(key) == 0 {
+		return argon2Params{}, nil, nil, fmt.Errorf("empty argon2 key")
+	}
+
+	return params, salt, key, nil
+}
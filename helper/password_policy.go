@@ -0,0 +1,135 @@
+package helper
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Password policy for new passwords.
+//
+//	PASSWORD_MIN_LENGTH             defaults to 8
+//	PASSWORD_MAX_LENGTH             defaults to 128
+//	PASSWORD_MIN_CHARACTER_CLASSES  how many of lowercase, uppercase, digits and symbols, defaults to 3
+//	BREACHED_PASSWORDS_DIR          optional, directory of SHA-1 range files, see CheckBreachedPassword
+var (
+	PASSWORD_MIN_LENGTH            = GetEnvInt("PASSWORD_MIN_LENGTH", 8)
+	PASSWORD_MAX_LENGTH            = GetEnvInt("PASSWORD_MAX_LENGTH", 128)
+	PASSWORD_MIN_CHARACTER_CLASSES = GetEnvInt("PASSWORD_MIN_CHARACTER_CLASSES", 3)
+	BREACHED_PASSWORDS_DIR         = os.Getenv("BREACHED_PASSWORDS_DIR")
+)
+
+const (
+	// BreachedPasswordPrefixLength is how many hex characters of the SHA-1
+	// name a range file, like the Have I Been Pwned range API.
+	BreachedPasswordPrefixLength = 5
+
+	// identifiers shorter than this are too common to reject inside a password
+	minIdentifierLength = 3
+)
+
+// CheckPasswordPolicy returns a message for every rule the password breaks.
+// username and email belong to the account and may not appear in the password.
+func CheckPasswordPolicy(password string, username string, email string) ([]string, error) {
+	violations := []string{}
+
+	length := utf8.RuneCountInString(password)
+	if length < PASSWORD_MIN_LENGTH {
+		violations = append(violations, fmt.Sprintf("password must be at least %d characters", PASSWORD_MIN_LENGTH))
+	}
+	if length > PASSWORD_MAX_LENGTH {
+		violations = append(violations, fmt.Sprintf("password must be at most %d characters", PASSWORD_MAX_LENGTH))
+	}
+
+	if characterClasses(password) < PASSWORD_MIN_CHARACTER_CLASSES {
+		violations = append(violations, fmt.Sprintf("password must contain at least %d of: lowercase letters, uppercase letters, digits, symbols", PASSWORD_MIN_CHARACTER_CLASSES))
+	}
+
+	lower := strings.ToLower(password)
+	if len(username) >= minIdentifierLength && strings.Contains(lower, strings.ToLower(username)) {
+		violations = append(violations, "password must not contain the username")
+	}
+
+	localPart, _, _ := strings.Cut(email, "@")
+	if len(localPart) >= minIdentifierLength && strings.Contains(lower, strings.ToLower(localPart)) {
+		violations = append(violations, "password must not contain the email address")
+	}
+
+	breached, err := CheckBreachedPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	if breached {
+		violations = append(violations, "password appears in a known data breach, choose a different one")
+	}
+
+	return violations, nil
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	classes := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			classes++
+		}
+	}
+	return classes
+}
+
+// CheckBreachedPassword looks the password up in BREACHED_PASSWORDS_DIR. The
+// directory holds one file per SHA-1 prefix, named by the first five upper
+// case hex characters with or without .txt, listing "SUFFIX:COUNT" lines like
+// the Have I Been Pwned range downloads. Only the matching file is read.
+func CheckBreachedPassword(password string) (bool, error) {
+	if BREACHED_PASSWORDS_DIR == "" {
+		return false, nil
+	}
+
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:BreachedPasswordPrefixLength], hash[BreachedPasswordPrefixLength:]
+
+	file, err := os.Open(filepath.Join(BREACHED_PASSWORDS_DIR, prefix+".txt"))
+	if errors.Is(err, fs.ErrNotExist) {
+		file, err = os.Open(filepath.Join(BREACHED_PASSWORDS_DIR, prefix))
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), ":")
+		if strings.EqualFold(strings.TrimSpace(line), suffix) {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
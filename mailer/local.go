@@ -0,0 +1,58 @@
+package mailer
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileMailer appends every message to a local file, useful for development and tests.
+// Like LogMailer it keeps the tokens in the messages readable on disk.
+type FileMailer struct {
+	mu   sync.Mutex
+	path string
+	from string
+}
+
+func NewFileMailer(path string, from string) *FileMailer {
+	return &FileMailer{
+		path: path,
+		from: from,
+	}
+}
+
+func (fm *FileMailer) Send(to string, subject string, body string) error {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	file, err := os.OpenFile(fm.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(buildMessage(fm.from, to, subject, body), "\r\n\r\n"...))
+	return err
+}
+
+// LogMailer writes messages to the standard logger instead of sending them.
+// Bodies carry reset and verification tokens, so it is for development only.
+type LogMailer struct {
+	from string
+}
+
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{
+		from: from,
+	}
+}
+
+func (lm *LogMailer) Send(to string, subject string, body string) error {
+	log.Printf("mail from %s to %s: %s\n%s", lm.from, to, subject, body)
+	return nil
+}
@@ -0,0 +1,70 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// MAILER selects the implementation: "smtp", "file" or "log". It has no
+// default, "file" and "log" keep full messages with their tokens on local
+// disk or in the logs and are only meant for development.
+var (
+	MAILER        = os.Getenv("MAILER")
+	MAIL_FROM     = os.Getenv("MAIL_FROM")
+	MAIL_FILE     = os.Getenv("MAIL_FILE")
+	SMTP_HOST     = os.Getenv("SMTP_HOST")
+	SMTP_PORT     = os.Getenv("SMTP_PORT")
+	SMTP_USERNAME = os.Getenv("SMTP_USERNAME")
+	SMTP_PASSWORD = os.Getenv("SMTP_PASSWORD")
+)
+
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
+
+func NewMailer() (Mailer, error) {
+	from := MAIL_FROM
+	if from == "" {
+		from = "MyGram <no-reply@mygram.local>"
+	}
+
+	switch MAILER {
+	case "smtp":
+		if SMTP_HOST == "" || SMTP_PORT == "" {
+			return nil, fmt.Errorf("SMTP_HOST and SMTP_PORT are required for the smtp mailer")
+		}
+		return NewSMTPMailer(SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD, from), nil
+	case "file":
+		if MAIL_FILE == "" {
+			return nil, fmt.Errorf("MAIL_FILE is required for the file mailer")
+		}
+		log.Printf("WARNING: MAILER=file writes every email, tokens included, to %s. Use smtp in production", MAIL_FILE)
+		return NewFileMailer(MAIL_FILE, from), nil
+	case "log":
+		log.Printf("WARNING: MAILER=log writes every email, tokens included, to the log. Use smtp in production")
+		return NewLogMailer(from), nil
+	case "":
+		return nil, fmt.Errorf("MAILER is not set, use smtp, or file or log for development")
+	default:
+		return nil, fmt.Errorf("unknown MAILER %q", MAILER)
+	}
+}
+
+func buildMessage(from string, to string, subject string, body string) []byte {
+	var msg strings.Builder
+	msg.WriteString("From: " + sanitizeHeader(from) + "\r\n")
+	msg.WriteString("To: " + sanitizeHeader(to) + "\r\n")
+	msg.WriteString("Subject: " + sanitizeHeader(subject) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(body)
+	return []byte(msg.String())
+}
+
+// sanitizeHeader drops line breaks so user supplied values cannot add headers.
+func sanitizeHeader(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
@@ -2,16 +2,39 @@ package main
 
 import (
 	"finalProject/database"
+	"finalProject/helper"
 	"finalProject/router"
 	"os"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+// TRUSTED_PROXIES lists the proxies, as IPs or CIDRs separated by commas, whose
+// X-Forwarded-For header is believed. Without it the client IP is the address
+// of the connection, so clients cannot pick their own IP for the login throttle.
+var TRUSTED_PROXIES = os.Getenv("TRUSTED_PROXIES")
+
 func main() {
 
 	routers := gin.Default()
 
+	var trustedProxies []string
+	for _, proxy := range strings.Split(TRUSTED_PROXIES, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+	err := routers.SetTrustedProxies(trustedProxies)
+	if err != nil {
+		panic(err)
+	}
+
+	err = helper.LoadKeyRing()
+	if err != nil {
+		panic(err)
+	}
+
 	database.StartDB()
 	db := database.GetDB()
 	router.StartApp(routers, db)
@@ -3,14 +3,46 @@ package middleware
 import (
 	"finalProject/helper"
 	"finalProject/model"
+	"finalProject/repository"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
 )
 
-func AuthMiddleware(ctx *gin.Context) {
+type Authenticator struct {
+	RevocationRepository          repository.IRevocationRepository
+	PersonalAccessTokenRepository repository.IPersonalAccessTokenRepository
+	SessionRepository             repository.ISessionRepository
+}
+
+func NewAuthenticator(revocationRepository repository.IRevocationRepository, personalAccessTokenRepository repository.IPersonalAccessTokenRepository, sessionRepository repository.ISessionRepository) *Authenticator {
+	return &Authenticator{
+		RevocationRepository:          revocationRepository,
+		PersonalAccessTokenRepository: personalAccessTokenRepository,
+		SessionRepository:             sessionRepository,
+	}
+}
+
+// AuthMiddleware accepts access tokens from login. Personal access tokens are
+// recognised but refused, they only work on routes using ScopedAuthMiddleware.
+func (a *Authenticator) AuthMiddleware(ctx *gin.Context) {
+	a.authenticate(ctx, "")
+}
+
+// ScopedAuthMiddleware accepts access tokens from login and personal access
+// tokens holding the resource scope: resource:read for GET requests and
+// resource:write for everything else.
+func (a *Authenticator) ScopedAuthMiddleware(resource string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		a.authenticate(ctx, resource)
+	}
+}
+
+func (a *Authenticator) authenticate(ctx *gin.Context, resource string) {
 	auth := ctx.GetHeader("Authorization")
 
 	if auth == "" {
@@ -21,11 +53,12 @@ func AuthMiddleware(ctx *gin.Context) {
 			},
 			Error: model.ErrorNotAuthorized.Err,
 		})
+		return
 	}
 
-	token := strings.Split(auth, " ")[1]
+	parts := strings.Split(auth, " ")
 
-	if token == "" {
+	if len(parts) != 2 || parts[1] == "" {
 		ctx.AbortWithStatusJSON(http.StatusUnauthorized, model.FailedResponse{
 			Meta: model.Meta{
 				Code:    http.StatusUnauthorized,
@@ -33,9 +66,58 @@ func AuthMiddleware(ctx *gin.Context) {
 			},
 			Error: model.ErrorNotAuthorized.Err,
 		})
+		return
+	}
+	token := parts[1]
+
+	if strings.HasPrefix(token, model.PersonalAccessTokenPrefix) {
+		a.authenticatePersonalAccessToken(ctx, token, resource)
+		return
 	}
 
 	jwtToken, err := helper.VerifyAccessToken(token)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusUnauthorized,
+				Message: http.StatusText(http.StatusUnauthorized),
+			},
+			Error: err.Error(),
+		})
+		return
+	}
+
+	claims, ok := jwtToken.Claims.(jwt.MapClaims)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+			Error: model.ErrorInvalidToken.Err,
+		})
+		return
+
+	}
+
+	userID, _ := claims["user_id"].(string)
+	jti, _ := claims["jti"].(string)
+	sessionID, _ := claims["sid"].(string)
+	role, _ := claims["role"].(string)
+	issuedAt, _ := claims["iat"].(float64)
+	expiresAt, _ := claims["exp"].(float64)
+	if userID == "" || jti == "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusUnauthorized,
+				Message: http.StatusText(http.StatusUnauthorized),
+			},
+			Error: model.ErrorInvalidToken.Err,
+		})
+		return
+	}
+
+	revoked, err := a.RevocationRepository.IsRevoked(jti, userID, time.Unix(int64(issuedAt), 0))
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
 			Meta: model.Meta{
@@ -47,8 +129,19 @@ func AuthMiddleware(ctx *gin.Context) {
 		return
 	}
 
-	claims, ok := jwtToken.Claims.(jwt.MapClaims)
-	if !ok {
+	if revoked {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusUnauthorized,
+				Message: http.StatusText(http.StatusUnauthorized),
+			},
+			Error: model.ErrorTokenRevoked.Err,
+		})
+		return
+	}
+
+	session, err := a.SessionRepository.GetByID(sessionID)
+	if err != nil && err != model.ErrorNotFound {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
 			Meta: model.Meta{
 				Code:    http.StatusInternalServerError,
@@ -57,10 +150,147 @@ func AuthMiddleware(ctx *gin.Context) {
 			Error: err.Error(),
 		})
 		return
+	}
 
+	if err == model.ErrorNotFound || session.RevokedAt != nil || session.UserID != userID {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusUnauthorized,
+				Message: http.StatusText(http.StatusUnauthorized),
+			},
+			Error: model.ErrorSessionRevoked.Err,
+		})
+		return
+	}
+
+	// last seen is informational, a failed write should not block the request
+	err = a.SessionRepository.Touch(session.ID, ctx.ClientIP())
+	if err != nil {
+		log.Printf("update last seen of session %s: %v", session.ID, err)
 	}
 
-	ctx.Set("user_id", claims["user_id"])
+	ctx.Set("user_id", userID)
+	ctx.Set("jti", jti)
+	ctx.Set("session_id", sessionID)
+	ctx.Set("role", role)
+	ctx.Set("token_expires_at", time.Unix(int64(expiresAt), 0))
 
 	ctx.Next()
 }
+
+// authenticatePersonalAccessToken lets a personal access token act as its
+// owner with the plain user role, limited to the granted scopes.
+func (a *Authenticator) authenticatePersonalAccessToken(ctx *gin.Context, token string, resource string) {
+	if resource == "" {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusForbidden,
+				Message: http.StatusText(http.StatusForbidden),
+			},
+			Error: model.ErrorPersonalAccessTokenNotAllowed.Err,
+		})
+		return
+	}
+
+	stored, err := a.PersonalAccessTokenRepository.GetByHash(helper.HashToken(token))
+	if err != nil {
+		if err != model.ErrorNotFound {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, model.FailedResponse{
+				Meta: model.Meta{
+					Code:    http.StatusInternalServerError,
+					Message: http.StatusText(http.StatusInternalServerError),
+				},
+				Error: err.Error(),
+			})
+			return
+		}
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusUnauthorized,
+				Message: http.StatusText(http.StatusUnauthorized),
+			},
+			Error: model.ErrorInvalidToken.Err,
+		})
+		return
+	}
+
+	if stored.RevokedAt != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusUnauthorized,
+				Message: http.StatusText(http.StatusUnauthorized),
+			},
+			Error: model.ErrorTokenRevoked.Err,
+		})
+		return
+	}
+
+	if stored.ExpiresAt != nil && time.Now().After(*stored.ExpiresAt) {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusUnauthorized,
+				Message: http.StatusText(http.StatusUnauthorized),
+			},
+			Error: model.ErrorInvalidToken.Err,
+		})
+		return
+	}
+
+	required := resource + ":write"
+	if ctx.Request.Method == http.MethodGet || ctx.Request.Method == http.MethodHead {
+		required = resource + ":read"
+	}
+
+	granted := false
+	for _, scope := range strings.Fields(stored.Scopes) {
+		if scope == required {
+			granted = true
+			break
+		}
+	}
+
+	if !granted {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusForbidden,
+				Message: http.StatusText(http.StatusForbidden),
+			},
+			Error: model.ErrorInsufficientScope.Err,
+		})
+		return
+	}
+
+	// last use is informational, a failed write should not block the request
+	err = a.PersonalAccessTokenRepository.TouchLastUsed(stored.ID)
+	if err != nil {
+		log.Printf("update last use of personal access token %s: %v", stored.ID, err)
+	}
+
+	ctx.Set("user_id", stored.UserID)
+	ctx.Set("role", model.RoleUser)
+	ctx.Set("token_id", stored.ID)
+
+	ctx.Next()
+}
+
+// RequireRole only lets through tokens carrying one of the roles. It must run
+// after AuthMiddleware, which puts the role from the token in the context.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		role := ctx.GetString("role")
+		for _, allowed := range roles {
+			if role == allowed {
+				ctx.Next()
+				return
+			}
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusForbidden, model.FailedResponse{
+			Meta: model.Meta{
+				Code:    http.StatusForbidden,
+				Message: http.StatusText(http.StatusForbidden),
+			},
+			Error: model.ErrorForbiddenAccess.Err,
+		})
+	}
+}
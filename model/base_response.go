@@ -15,6 +15,12 @@ type FailedResponse struct {
 	Error string `json:"error"`
 }
 
+type FieldFailedResponse struct {
+	Meta   Meta         `json:"meta"`
+	Error  string       `json:"error"`
+	Fields []FieldError `json:"fields"`
+}
+
 type HomeInformationResponse struct {
 	About    string `json:"About"`
 	Name     string `json:"Name"`
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Block hides the two users from each other: neither sees the other's photos
+// and comments, and they cannot comment on or follow each other.
+type Block struct {
+	BlockerID string `gorm:"primaryKey;type:varchar(255)"`
+	BlockedID string `gorm:"primaryKey;type:varchar(255);index"`
+	CreatedAt time.Time
+}
+
+// Mute hides the photos and comments of MutedID from the listings of MuterID.
+// The muted user is not told and can still interact.
+type Mute struct {
+	MuterID   string `gorm:"primaryKey;type:varchar(255)"`
+	MutedID   string `gorm:"primaryKey;type:varchar(255);index"`
+	CreatedAt time.Time
+}
+
+// Response
+type UserRelationResponse struct {
+	ID       string    `json:"id"`
+	Username string    `json:"username"`
+	Since    time.Time `json:"since"`
+}
+
+type UserRelationListResponse struct {
+	Users []UserRelationResponse `json:"users"`
+	Page  PageResponse           `json:"page"`
+}
@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Comment struct {
 	CommentID string `gorm:"primaryKey;type:varchar(255)"`
@@ -9,6 +13,8 @@ type Comment struct {
 	PhotoID   string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	DeletedBy string         `gorm:"type:varchar(255)" json:"-"`
 }
 
 //	Request
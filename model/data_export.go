@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// Statuses of a data export. A ready export turns expired once its download
+// link expires and the archive is removed.
+const (
+	DataExportPending    = "pending"
+	DataExportProcessing = "processing"
+	DataExportReady      = "ready"
+	DataExportFailed     = "failed"
+	DataExportExpired    = "expired"
+)
+
+// DataExport is a request for a ZIP archive of everything a user stored.
+// FilePath is where the archive is kept on the server and is never exposed.
+type DataExport struct {
+	ID          string `gorm:"primaryKey;type:varchar(255)"`
+	UserID      string `gorm:"not null;type:varchar(255);index"`
+	Status      string `gorm:"not null;type:varchar(16);index"`
+	FilePath    string `gorm:"type:varchar(512)"`
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// DataExportContent is what goes into the archive.
+type DataExportContent struct {
+	Profile          UserResponse
+	Photos           []PhotoAllResponse
+	CommentsWritten  []CommentResponse
+	CommentsReceived []CommentResponse
+	SocialMedias     []SocialMediaResponse
+}
+
+// Response
+
+// DataExportResponse has a DownloadURL once the export is ready, the link
+// stops working at ExpiresAt.
+type DataExportResponse struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// FeedItem puts a photo in the feed of a user. CreatedAt is the time the
+// photo was posted so the feed keeps the photo order.
+type FeedItem struct {
+	UserID    string    `gorm:"primaryKey;type:varchar(255);index:idx_feed_items_user_created,priority:1"`
+	PhotoID   string    `gorm:"primaryKey;type:varchar(255);index"`
+	AuthorID  string    `gorm:"not null;type:varchar(255);index"`
+	CreatedAt time.Time `gorm:"index:idx_feed_items_user_created,priority:2,sort:desc"`
+}
+
+// FeedPullAuthor marks a user with too many followers to fan out to. Their
+// photos are read from the photos table when a follower loads the feed.
+type FeedPullAuthor struct {
+	UserID    string `gorm:"primaryKey;type:varchar(255)"`
+	CreatedAt time.Time
+}
+
+// FeedCursor is the position of the last photo of a feed page.
+type FeedCursor struct {
+	CreatedAt time.Time
+	PhotoID   string
+}
+
+// Response
+type FeedResponse struct {
+	Photos []PhotoAllResponse `json:"photos"`
+	Page   CursorResponse     `json:"page"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// Follow means FollowerID sees the photos of FolloweeID. The pair is the key,
+// so following twice stores one row.
+type Follow struct {
+	FollowerID string `gorm:"primaryKey;type:varchar(255)"`
+	FolloweeID string `gorm:"primaryKey;type:varchar(255);index"`
+	CreatedAt  time.Time
+}
+
+// FollowRequest is a pending follow of a private account, it becomes a Follow
+// when the account owner accepts it.
+type FollowRequest struct {
+	RequesterID string `gorm:"primaryKey;type:varchar(255)"`
+	TargetID    string `gorm:"primaryKey;type:varchar(255);index"`
+	CreatedAt   time.Time
+}
+
+const (
+	FollowStatusFollowing = "following"
+	FollowStatusRequested = "requested"
+)
+
+// Request
+type UserPrivacyRequest struct {
+	IsPrivate bool `json:"is_private"`
+}
+
+// Response
+type FollowStatusResponse struct {
+	Status string `json:"status"`
+}
+
+type FollowUserResponse struct {
+	ID         string    `json:"id"`
+	Username   string    `json:"username"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+type FollowListResponse struct {
+	Users []FollowUserResponse `json:"users"`
+	Page  PageResponse         `json:"page"`
+}
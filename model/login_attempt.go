@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// LoginAttempt counts consecutive failed logins for one key, either an account or a client IP.
+type LoginAttempt struct {
+	Key         string
+	Failures    int
+	LastFailure time.Time
+	LockedUntil time.Time
+}
+
+// LoginLockout is the audit record written every time a key gets locked.
+type LoginLockout struct {
+	ID          string `gorm:"primaryKey;type:varchar(255)"`
+	Key         string `gorm:"not null;type:varchar(255);index"`
+	Failures    int
+	LockedUntil time.Time
+	UnlockedAt  *time.Time
+	CreatedAt   time.Time
+}
+
+// ClientInfo describes where a login request comes from.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
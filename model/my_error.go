@@ -1,5 +1,11 @@
 package model
 
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
 type MyError struct {
 	Err string `json:"error"`
 }
@@ -8,6 +14,38 @@ func (me MyError) Error() string {
 	return me.Err
 }
 
+// FieldError is an error caused by the value of a single request field.
+type FieldError struct {
+	Field string `json:"field"`
+	Err   string `json:"error"`
+}
+
+func (fe FieldError) Error() string {
+	return fe.Err
+}
+
+// PasswordPolicyError lists every rule a new password breaks.
+type PasswordPolicyError struct {
+	Fields []FieldError
+}
+
+func (pe PasswordPolicyError) Error() string {
+	return "password does not meet the password policy"
+}
+
+// TooManyAttemptsError is returned while a login is backing off or locked out.
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (te TooManyAttemptsError) RetryAfterSeconds() int {
+	return int(math.Ceil(te.RetryAfter.Seconds()))
+}
+
+func (te TooManyAttemptsError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, try again in %d seconds", te.RetryAfterSeconds())
+}
+
 var (
 	ErrorInvalidEmailOrPassword = MyError{
 		Err: "invalid email / password",
@@ -17,6 +55,136 @@ var (
 		Err: "invalid token",
 	}
 
+	ErrorInvalidRefreshToken = MyError{
+		Err: "invalid refresh token",
+	}
+
+	ErrorRefreshTokenReused = MyError{
+		Err: "refresh token already used, please login again",
+	}
+
+	ErrorTokenRevoked = MyError{
+		Err: "token has been revoked",
+	}
+
+	ErrorSessionRevoked = MyError{
+		Err: "session has ended, please login again",
+	}
+
+	ErrorWrongPassword = MyError{
+		Err: "wrong password",
+	}
+
+	ErrorInvalidResetToken = MyError{
+		Err: "invalid or expired reset token",
+	}
+
+	ErrorEmailNotVerified = MyError{
+		Err: "email is not verified, check your inbox for the verification link",
+	}
+
+	ErrorInvalidVerificationToken = MyError{
+		Err: "invalid or expired verification link",
+	}
+
+	ErrorInvalidChallengeToken = MyError{
+		Err: "invalid or expired login challenge, please login again",
+	}
+
+	ErrorInvalidTwoFactorCode = MyError{
+		Err: "invalid authentication code",
+	}
+
+	ErrorTwoFactorAlreadyEnabled = MyError{
+		Err: "two-factor authentication is already enabled",
+	}
+
+	ErrorTwoFactorNotEnabled = MyError{
+		Err: "two-factor authentication is not enabled",
+	}
+
+	ErrorTwoFactorNotSetUp = MyError{
+		Err: "two-factor authentication has not been set up",
+	}
+
+	ErrorCannotChangeOwnRole = MyError{
+		Err: "admins cannot change their own role",
+	}
+
+	ErrorInvalidScope = FieldError{
+		Field: "scopes",
+		Err:   "scopes must be a non-empty list of photos:read, photos:write, comments:read, comments:write, social_media:read, social_media:write",
+	}
+
+	ErrorTokenExpiryTooLong = FieldError{
+		Field: "expires_in_days",
+		Err:   "expires_in_days is longer than the maximum token lifetime",
+	}
+
+	ErrorPersonalAccessTokenNotAllowed = MyError{
+		Err: "personal access tokens cannot be used on this route",
+	}
+
+	ErrorInsufficientScope = MyError{
+		Err: "token does not have the required scope",
+	}
+
+	ErrorOIDCNotConfigured = MyError{
+		Err: "sign in with an external provider is not configured",
+	}
+
+	ErrorInvalidOIDCState = MyError{
+		Err: "invalid or expired sign in attempt, please start again",
+	}
+
+	ErrorOIDCLoginFailed = MyError{
+		Err: "sign in with the external provider failed",
+	}
+
+	ErrorOIDCEmailRequired = MyError{
+		Err: "the external provider did not share an email address",
+	}
+
+	ErrorOIDCAccountExists = MyError{
+		Err: "an account with this email already exists, verify the email of both accounts or login with your password",
+	}
+
+	ErrorCannotFollowSelf = MyError{
+		Err: "you cannot follow yourself",
+	}
+
+	ErrorPrivateAccount = MyError{
+		Err: "this account is private",
+	}
+
+	ErrorCannotBlockSelf = MyError{
+		Err: "you cannot block yourself",
+	}
+
+	ErrorCannotMuteSelf = MyError{
+		Err: "you cannot mute yourself",
+	}
+
+	ErrorAccountDeleted = MyError{
+		Err: "this account was deleted",
+	}
+
+	ErrorAgeLocked = MyError{
+		Err: "your age was set by an admin and can only be changed by one",
+	}
+
+	ErrorInvalidCursor = MyError{
+		Err: "invalid cursor",
+	}
+
+	ErrorExportNotReady = MyError{
+		Err: "the export is not ready yet",
+	}
+
+	ErrorExportExpired = MyError{
+		Err: "the export has expired, request a new one",
+	}
+
 	ErrorNotAuthorized = MyError{
 		Err: "Not Authorized",
 	}
@@ -28,4 +196,14 @@ var (
 	ErrorForbiddenAccess = MyError{
 		Err: "Forbidden Access",
 	}
+
+	ErrorEmailAlreadyExists = FieldError{
+		Field: "email",
+		Err:   "email is already registered",
+	}
+
+	ErrorUsernameAlreadyExists = FieldError{
+		Field: "username",
+		Err:   "username is already taken",
+	}
 )
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// UserIdentity links an account at an external OpenID Connect provider,
+// identified by issuer and subject, to a MyGram user.
+type UserIdentity struct {
+	ID        string `gorm:"primaryKey;type:varchar(255)"`
+	UserID    string `gorm:"not null;type:varchar(255);index"`
+	Issuer    string `gorm:"not null;type:varchar(255);uniqueIndex:idx_user_identity_subject"`
+	Subject   string `gorm:"not null;type:varchar(255);uniqueIndex:idx_user_identity_subject"`
+	Email     string `gorm:"type:varchar(255)"`
+	CreatedAt time.Time
+}
+
+// OIDCLoginState remembers a started provider login until its callback. The
+// state is stored hashed and can be consumed once.
+type OIDCLoginState struct {
+	StateHash    string    `gorm:"primaryKey;type:varchar(255)"`
+	Nonce        string    `gorm:"not null;type:varchar(255)"`
+	CodeVerifier string    `gorm:"not null;type:varchar(255)"`
+	ExpiresAt    time.Time `gorm:"index"`
+	CreatedAt    time.Time
+}
+
+// Request
+type OIDCCallbackRequest struct {
+	Code             string `form:"code"`
+	State            string `form:"state"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
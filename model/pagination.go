@@ -0,0 +1,61 @@
+package model
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// PageQuery is the page and limit query of a paginated listing, pages start at 1.
+type PageQuery struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+// Normalize fills in the defaults and caps the limit.
+func (pq PageQuery) Normalize() PageQuery {
+	if pq.Page < 1 {
+		pq.Page = 1
+	}
+	if pq.Limit < 1 {
+		pq.Limit = DefaultPageLimit
+	}
+	if pq.Limit > MaxPageLimit {
+		pq.Limit = MaxPageLimit
+	}
+	return pq
+}
+
+func (pq PageQuery) Offset() int {
+	return (pq.Page - 1) * pq.Limit
+}
+
+// CursorQuery is the cursor and limit query of a listing read page by page
+// with the next cursor of the previous page. An empty cursor starts at the top.
+type CursorQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+// Normalize fills in the default and caps the limit.
+func (cq CursorQuery) Normalize() CursorQuery {
+	if cq.Limit < 1 {
+		cq.Limit = DefaultPageLimit
+	}
+	if cq.Limit > MaxPageLimit {
+		cq.Limit = MaxPageLimit
+	}
+	return cq
+}
+
+// Response
+type PageResponse struct {
+	Page  int   `json:"page"`
+	Limit int   `json:"limit"`
+	Total int64 `json:"total"`
+}
+
+// CursorResponse has an empty NextCursor on the last page.
+type CursorResponse struct {
+	NextCursor string `json:"next_cursor"`
+	Limit      int    `json:"limit"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+type PasswordReset struct {
+	ID        string `gorm:"primaryKey;type:varchar(255)"`
+	UserID    string `gorm:"not null;type:varchar(255);index"`
+	TokenHash string `gorm:"unique;not null;type:varchar(255)"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// Request
+type UserChangePasswordRequest struct {
+	OldPassword string `json:"old_password" valid:"required~Old password is required"`
+	NewPassword string `json:"new_password" valid:"required~New password is required"`
+}
+
+type UserForgotPasswordRequest struct {
+	Email string `json:"email" valid:"required,email"`
+}
+
+type UserResetPasswordRequest struct {
+	Token       string `json:"token" valid:"required~Reset token is required"`
+	NewPassword string `json:"new_password" valid:"required~New password is required"`
+}
@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+// PersonalAccessTokenPrefix marks personal access tokens so the auth
+// middleware can tell them apart from JWT access tokens.
+const PersonalAccessTokenPrefix = "mgp_"
+
+// Scopes a personal access token can be granted. A read scope allows GET
+// requests on its route group, a write scope every other method.
+const (
+	ScopePhotosRead       = "photos:read"
+	ScopePhotosWrite      = "photos:write"
+	ScopeCommentsRead     = "comments:read"
+	ScopeCommentsWrite    = "comments:write"
+	ScopeSocialMediaRead  = "social_media:read"
+	ScopeSocialMediaWrite = "social_media:write"
+)
+
+var PersonalAccessTokenScopes = []string{
+	ScopePhotosRead,
+	ScopePhotosWrite,
+	ScopeCommentsRead,
+	ScopeCommentsWrite,
+	ScopeSocialMediaRead,
+	ScopeSocialMediaWrite,
+}
+
+// PersonalAccessToken is stored hashed, Scopes is a space separated list.
+type PersonalAccessToken struct {
+	ID         string `gorm:"primaryKey;type:varchar(255)"`
+	UserID     string `gorm:"not null;type:varchar(255);index"`
+	Name       string `gorm:"not null;type:varchar(255)"`
+	TokenHash  string `gorm:"unique;not null;type:varchar(255)"`
+	Scopes     string `gorm:"not null;type:varchar(255)"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// Request
+type PersonalAccessTokenCreateRequest struct {
+	Name          string   `json:"name" valid:"required~Name is required,maxstringlength(100)~Name is at most 100 characters"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expires_in_days" valid:"range(0|365)~Expiry is at most 365 days"`
+}
+
+// Response
+type PersonalAccessTokenResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type PersonalAccessTokenCreateResponse struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Token     string     `json:"token"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -2,56 +2,76 @@ package model
 
 import (
 	"time"
+
+	"gorm.io/gorm"
+)
+
+// Content ratings of a photo, mature photos are only shown to users of at
+// least the mature content age.
+const (
+	ContentRatingGeneral = "general"
+	ContentRatingMature  = "mature"
 )
 
 type Photo struct {
-	PhotoID   string `gorm:"primaryKey;type:varchar(255)"`
-	Title     string `gorm:"not null;type:varchar(255);default:null"`
-	PhotoUrl  string `gorm:"not null;type:varchar(255);default:null"`
-	UserID    string
-	Comments  []Comment
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	PhotoID       string `gorm:"primaryKey;type:varchar(255)"`
+	Title         string `gorm:"not null;type:varchar(255);default:null"`
+	PhotoUrl      string `gorm:"not null;type:varchar(255);default:null"`
+	ContentRating string `gorm:"not null;type:varchar(16);default:general"`
+	UserID        string `gorm:"index:idx_photos_user_created,priority:1"`
+	Comments      []Comment
+	CreatedAt     time.Time `gorm:"index:idx_photos_user_created,priority:2"`
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
+	DeletedBy     string         `gorm:"type:varchar(255)"`
 }
 
 // Request
+
+// PhotoRequest leaves ContentRating empty for general on create and to keep
+// the current rating on update.
 type PhotoRequest struct {
-	Title    string `json:"title" valid:"required~Photo Title is Required"`
-	PhotoUrl string `json:"photo_url" valid:"required~Photo URL is required"`
+	Title         string `json:"title" valid:"required~Photo Title is Required"`
+	PhotoUrl      string `json:"photo_url" valid:"required~Photo URL is required"`
+	ContentRating string `json:"content_rating" valid:"in(general|mature)~Content rating must be general or mature"`
 }
 
 // Response
 type PhotoCreateResponse struct {
-	PhotoID   string    `json:"photo_id"`
-	Title     string    `json:"title"`
-	PhotoUrl  string    `json:"photo_url"`
-	UserID    string    `json:"user_id"`
-	CreatedAt time.Time `json:"created_at"`
+	PhotoID       string    `json:"photo_id"`
+	Title         string    `json:"title"`
+	PhotoUrl      string    `json:"photo_url"`
+	ContentRating string    `json:"content_rating"`
+	UserID        string    `json:"user_id"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
 type PhotoUpdateResponse struct {
-	PhotoID   string    `json:"photo_id"`
-	Title     string    `json:"title"`
-	PhotoUrl  string    `json:"photo_url"`
-	UserID    string    `json:"user_id"`
-	UpdatedAt time.Time `json:"updated_at"`
+	PhotoID       string    `json:"photo_id"`
+	Title         string    `json:"title"`
+	PhotoUrl      string    `json:"photo_url"`
+	ContentRating string    `json:"content_rating"`
+	UserID        string    `json:"user_id"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 type PhotoResponse struct {
-	PhotoID   string    `json:"photo_id"`
-	Title     string    `json:"title"`
-	PhotoUrl  string    `json:"photo_url"`
-	UserID    string    `json:"user_id"`
-	Comments  []Comment `json:"comments"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	PhotoID       string    `json:"photo_id"`
+	Title         string    `json:"title"`
+	PhotoUrl      string    `json:"photo_url"`
+	ContentRating string    `json:"content_rating"`
+	UserID        string    `json:"user_id"`
+	Comments      []Comment `json:"comments"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 type PhotoAllResponse struct {
-	PhotoID   string    `json:"photo_id"`
-	Title     string    `json:"title"`
-	PhotoUrl  string    `json:"photo_url"`
-	UserID    string    `json:"user_id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	PhotoID       string    `json:"photo_id"`
+	Title         string    `json:"title"`
+	PhotoUrl      string    `json:"photo_url"`
+	ContentRating string    `json:"content_rating"`
+	UserID        string    `json:"user_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
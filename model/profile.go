@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// Response
+
+// ProfileResponse is what any logged in user can see about another user, it
+// must never carry the email, age or password hash.
+type ProfileResponse struct {
+	ID             string                       `json:"id"`
+	Username       string                       `json:"username"`
+	IsPrivate      bool                         `json:"is_private"`
+	JoinedAt       time.Time                    `json:"joined_at"`
+	PhotoCount     int64                        `json:"photo_count"`
+	CommentCount   int64                        `json:"comment_count"`
+	FollowerCount  int64                        `json:"follower_count"`
+	FollowingCount int64                        `json:"following_count"`
+	SocialMedias   []ProfileSocialMediaResponse `json:"social_medias"`
+	LatestPhotos   []ProfilePhotoResponse       `json:"latest_photos"`
+}
+
+type ProfileSocialMediaResponse struct {
+	Name           string `json:"name"`
+	SocialMediaUrl string `json:"social_media_url"`
+}
+
+type ProfilePhotoResponse struct {
+	PhotoID       string    `json:"photo_id"`
+	Title         string    `json:"title"`
+	PhotoUrl      string    `json:"photo_url"`
+	ContentRating string    `json:"content_rating"`
+	CreatedAt     time.Time `json:"created_at"`
+}
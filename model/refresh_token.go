@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// RefreshToken is stored hashed. Every token issued from the same login shares
+// a FamilyID so a replayed token can revoke the whole chain.
+type RefreshToken struct {
+	ID        string `gorm:"primaryKey;type:varchar(255)"`
+	UserID    string `gorm:"not null;type:varchar(255);index"`
+	FamilyID  string `gorm:"not null;type:varchar(255);index"`
+	TokenHash string `gorm:"unique;not null;type:varchar(255)"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// Request
+type UserRefreshRequest struct {
+	RefreshToken string `json:"refresh_token" valid:"required~Refresh token is required"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// RevokedToken blocks a single access token by its jti until it expires anyway.
+type RevokedToken struct {
+	JTI       string    `gorm:"primaryKey;type:varchar(255)"`
+	UserID    string    `gorm:"not null;type:varchar(255)"`
+	ExpiresAt time.Time `gorm:"index"`
+	CreatedAt time.Time
+}
+
+// UserRevocation blocks every access token of a user issued before RevokedBefore,
+// which is kept in whole seconds like the issue time of a token.
+type UserRevocation struct {
+	UserID        string `gorm:"primaryKey;type:varchar(255)"`
+	RevokedBefore time.Time
+	UpdatedAt     time.Time
+}
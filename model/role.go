@@ -0,0 +1,23 @@
+package model
+
+// Roles a user can have. Every account starts as RoleUser, only an admin can
+// change roles, so the first admin has to be promoted in the database.
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// CanModerate reports whether the role may edit or delete content owned by others.
+func CanModerate(role string) bool {
+	return role == RoleModerator || role == RoleAdmin
+}
+
+// Request
+type UserSetRoleRequest struct {
+	Role string `json:"role" valid:"required~Role is required,in(user|moderator|admin)~Role must be user, moderator or admin"`
+}
+
+type UserSetAgeRequest struct {
+	Age int `json:"age" valid:"required~Age is required,range(8|99)~minimum age to register is 8"`
+}
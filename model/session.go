@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Session is one login on one device. Its ID is the sid claim of the access
+// tokens and the FamilyID of the refresh tokens issued for the login.
+type Session struct {
+	ID         string `gorm:"primaryKey;type:varchar(255)"`
+	UserID     string `gorm:"not null;type:varchar(255);index"`
+	UserAgent  string `gorm:"type:varchar(512)"`
+	IP         string `gorm:"type:varchar(64)"`
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	RevokedAt  *time.Time
+}
+
+// Response
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
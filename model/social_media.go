@@ -2,6 +2,8 @@ package model
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type SocialMedia struct {
@@ -11,6 +13,8 @@ type SocialMedia struct {
 	UserID         string
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+	DeletedBy      string         `gorm:"type:varchar(255)"`
 }
 
 // Request
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// Response
+
+// TrashResponse lists what the user deleted and can still restore. PurgeAt
+// is when an item is removed for good.
+type TrashResponse struct {
+	Photos       []TrashPhotoResponse       `json:"photos"`
+	Comments     []TrashCommentResponse     `json:"comments"`
+	SocialMedias []TrashSocialMediaResponse `json:"social_medias"`
+}
+
+type TrashPhotoResponse struct {
+	PhotoID   string    `json:"photo_id"`
+	Title     string    `json:"title"`
+	PhotoUrl  string    `json:"photo_url"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+type TrashCommentResponse struct {
+	CommentID string    `json:"comment_id"`
+	Message   string    `json:"message"`
+	PhotoID   string    `json:"photo_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+type TrashSocialMediaResponse struct {
+	SocialID       string    `json:"social_id"`
+	Name           string    `json:"name"`
+	SocialMediaUrl string    `json:"social_media_url"`
+	DeletedAt      time.Time `json:"deleted_at"`
+	PurgeAt        time.Time `json:"purge_at"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+type RecoveryCode struct {
+	ID        string `gorm:"primaryKey;type:varchar(255)"`
+	UserID    string `gorm:"not null;type:varchar(255);index"`
+	CodeHash  string `gorm:"not null;type:varchar(255)"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// Request
+type UserLoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" valid:"required~Challenge token is required"`
+	Code           string `json:"code" valid:"required~Authentication code or recovery code is required"`
+}
+
+type TwoFactorEnableRequest struct {
+	Code string `json:"code" valid:"required~Authentication code is required"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" valid:"required~Password is required"`
+	Code     string `json:"code" valid:"required~Authentication code or recovery code is required"`
+}
+
+// Response
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorEnableResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -1,31 +1,57 @@
 package model
 
-import "time"
+import (
+	"time"
 
+	"gorm.io/gorm"
+)
+
+// User stores Age as reported on AgeRecordedAt, or on CreatedAt when it is
+// nil, and the current age is counted from there. AgeLockedAt is set when an admin sets the
+// age, after that the user can no longer change it.
 type User struct {
-	ID           string `gorm:"primaryKey;type:varchar(255)"`
-	Username     string `gorm:"unique;not null;type:varchar(255);default:null"`
-	Email        string `gorm:"unique;not null;type:varchar(255);default:null"`
-	Password     string `gorm:"not null;type:varchar(255)"`
-	Age          int    `gorm:"not null"`
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	SocialMedias []SocialMedia
-	Photos       []Photo
-	Comments     []Comment
+	ID              string `gorm:"primaryKey;type:varchar(255)"`
+	Username        string `gorm:"unique;not null;type:varchar(255);default:null"`
+	Email           string `gorm:"unique;not null;type:varchar(255);default:null"`
+	Password        string `gorm:"not null;type:varchar(255)"`
+	Age             int    `gorm:"not null"`
+	AgeRecordedAt   *time.Time
+	AgeLockedAt     *time.Time
+	Role            string `gorm:"not null;type:varchar(32);default:user"`
+	IsPrivate       bool   `gorm:"not null;default:false"`
+	EmailVerifiedAt *time.Time
+	TOTPSecret      string `gorm:"type:varchar(255)"`
+	TOTPEnabledAt   *time.Time
+	TOTPLastStep    int64
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       gorm.DeletedAt `gorm:"index"`
+	SocialMedias    []SocialMedia
+	Photos          []Photo
+	Comments        []Comment
 }
 
 // Request
 type UserRegisterRequest struct {
 	Username string `json:"username" valid:"required~Username is required"`
 	Email    string `json:"email" valid:"required,email"`
-	Password string `json:"password" valid:"required~Password is required,minstringlength(6)~Password atleast 6 characters"`
+	Password string `json:"password" valid:"required~Password is required"`
+	Age      int    `json:"age" valid:"required~Age is required,range(8|99)~minimum age to register is 8"`
+}
+
+type UserUpdateRequest struct {
+	Username string `json:"username" valid:"required~Username is required"`
+	Email    string `json:"email" valid:"required,email"`
 	Age      int    `json:"age" valid:"required~Age is required,range(8|99)~minimum age to register is 8"`
 }
 
+type UserResendVerificationRequest struct {
+	Email string `json:"email" valid:"required,email"`
+}
+
 type UserLoginRequest struct {
-	Email    string `json:"email" validate:"required~Username is required"`
-	Password string `json:"password" validate:"required~Password is required"`
+	Identifier string `json:"identifier" valid:"required~Email or username is required"`
+	Password   string `json:"password" valid:"required~Password is required"`
 }
 
 // Response
@@ -34,10 +60,29 @@ type UserRegisterResponse struct {
 	Username  string    `json:"username"`
 	Email     string    `json:"email"`
 	Age       int       `json:"age"`
+	IsPrivate bool      `json:"is_private"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// UserLoginResponse carries either the tokens or, for accounts with two-factor
+// authentication, a challenge token for POST /mygram/user/login/2fa.
 type UserLoginResponse struct {
-	Token string `json:"token"`
+	Token             string `json:"token,omitempty"`
+	RefreshToken      string `json:"refresh_token,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+}
+
+type UserResponse struct {
+	ID            string    `json:"id"`
+	Username      string    `json:"username"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+	TwoFactor     bool      `json:"two_factor_enabled"`
+	Age           int       `json:"age"`
+	Role          string    `json:"role"`
+	IsPrivate     bool      `json:"is_private"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
@@ -0,0 +1,13 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// CodeChallenge derives the S256 PKCE challenge (RFC 7636) sent with the
+// authorization request. The verifier itself only goes to the token endpoint.
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
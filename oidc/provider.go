@@ -0,0 +1,341 @@
+package oidc
+
+import (
+	"encoding/json"
+	"finalProject/model"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// OpenID Connect provider configuration. Sign in with the provider is only
+// offered when OIDC_ISSUER is set.
+//
+//	OIDC_ISSUER         issuer URL, discovery is read from {issuer}/.well-known/openid-configuration
+//	OIDC_CLIENT_ID      client registered at the provider
+//	OIDC_CLIENT_SECRET  optional, public clients rely on PKCE alone
+//	OIDC_REDIRECT_URL   the MyGram callback, for example https://api.example.com/mygram/user/oidc/callback
+//	OIDC_SCOPES         space separated, defaults to "openid email profile"
+var (
+	OIDC_ISSUER        = os.Getenv("OIDC_ISSUER")
+	OIDC_CLIENT_ID     = os.Getenv("OIDC_CLIENT_ID")
+	OIDC_CLIENT_SECRET = os.Getenv("OIDC_CLIENT_SECRET")
+	OIDC_REDIRECT_URL  = os.Getenv("OIDC_REDIRECT_URL")
+	OIDC_SCOPES        = os.Getenv("OIDC_SCOPES")
+)
+
+const (
+	httpTimeout = 10 * time.Second
+
+	// keyRefreshInterval stops tokens with unknown kids from making us
+	// download the provider keys on every request.
+	keyRefreshInterval = time.Minute
+
+	maxResponseSize = 1 << 20
+)
+
+// Claims are the parts of a verified ID token MyGram uses.
+type Claims struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+	Name              string
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider talks to one OpenID Connect provider with the authorization code
+// flow and PKCE. Discovery and keys are fetched on first use and cached.
+type Provider struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+
+	client *http.Client
+
+	mu            sync.Mutex
+	discovery     *discoveryDocument
+	keys          map[string]interface{}
+	keysFetchedAt time.Time
+}
+
+// NewProvider builds the provider from the environment. It returns nil when
+// OIDC_ISSUER is not set.
+func NewProvider() (*Provider, error) {
+	if OIDC_ISSUER == "" {
+		return nil, nil
+	}
+	if OIDC_CLIENT_ID == "" || OIDC_REDIRECT_URL == "" {
+		return nil, fmt.Errorf("OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required when OIDC_ISSUER is set")
+	}
+
+	scopes := strings.Fields(OIDC_SCOPES)
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &Provider{
+		Issuer:       strings.TrimSuffix(OIDC_ISSUER, "/"),
+		ClientID:     OIDC_CLIENT_ID,
+		ClientSecret: OIDC_CLIENT_SECRET,
+		RedirectURL:  OIDC_REDIRECT_URL,
+		Scopes:       scopes,
+		client:       &http.Client{Timeout: httpTimeout},
+	}, nil
+}
+
+// AuthCodeURL is where the user is sent to sign in at the provider.
+func (p *Provider) AuthCodeURL(state string, nonce string, codeChallenge string) (string, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.ClientID)
+	query.Set("redirect_uri", p.RedirectURL)
+	query.Set("scope", strings.Join(p.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange trades the authorization code for the raw ID token.
+func (p *Provider) Exchange(code string, codeVerifier string) (string, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.RedirectURL)
+	form.Set("client_id", p.ClientID)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.ClientID), url.QueryEscape(p.ClientSecret))
+	}
+
+	var tokenResponse struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := p.doJSON(req, &tokenResponse)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK || tokenResponse.Error != "" {
+		return "", fmt.Errorf("oidc token endpoint returned %d: %s %s", status, tokenResponse.Error, tokenResponse.ErrorDescription)
+	}
+	if tokenResponse.IDToken == "" {
+		return "", fmt.Errorf("oidc token endpoint returned no id_token")
+	}
+
+	return tokenResponse.IDToken, nil
+}
+
+// VerifyIDToken checks the signature against the provider keys and the
+// issuer, audience, expiry and nonce of the token.
+func (p *Provider) VerifyIDToken(rawIDToken string, nonce string) (Claims, error) {
+	token, err := jwt.Parse(rawIDToken, func(t *jwt.Token) (interface{}, error) {
+		switch t.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA, *jwt.SigningMethodEd25519:
+		default:
+			return nil, fmt.Errorf("unexpected id token algorithm %q", t.Method.Alg())
+		}
+
+		kid, _ := t.Header["kid"].(string)
+		return p.key(kid)
+	})
+	if err != nil {
+		return Claims{}, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return Claims{}, fmt.Errorf("unexpected id token claims")
+	}
+
+	if _, ok := claims["exp"]; !ok {
+		return Claims{}, fmt.Errorf("id token has no expiry")
+	}
+
+	issuer, _ := claims["iss"].(string)
+	if strings.TrimSuffix(issuer, "/") != p.Issuer {
+		return Claims{}, fmt.Errorf("id token issuer %q does not match %q", issuer, p.Issuer)
+	}
+
+	if !hasAudience(claims["aud"], p.ClientID) {
+		return Claims{}, fmt.Errorf("id token is not issued for this client")
+	}
+	if azp, ok := claims["azp"].(string); ok && azp != p.ClientID {
+		return Claims{}, fmt.Errorf("id token is authorized for another client")
+	}
+
+	tokenNonce, _ := claims["nonce"].(string)
+	if nonce == "" || tokenNonce != nonce {
+		return Claims{}, fmt.Errorf("id token nonce does not match")
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return Claims{}, fmt.Errorf("id token has no subject")
+	}
+
+	result := Claims{
+		Issuer:  issuer,
+		Subject: subject,
+	}
+	result.Email, _ = claims["email"].(string)
+	result.PreferredUsername, _ = claims["preferred_username"].(string)
+	result.Name, _ = claims["name"].(string)
+
+	// some providers send email_verified as a string
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		result.EmailVerified = verified
+	case string:
+		result.EmailVerified = verified == "true"
+	}
+
+	return result, nil
+}
+
+func hasAudience(aud interface{}, clientID string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == clientID
+	case []interface{}:
+		for _, a := range aud {
+			if a == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *Provider) discover() (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, p.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var discovery discoveryDocument
+	status, err := p.doJSON(req, &discovery)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("oidc discovery returned %d", status)
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != p.Issuer {
+		return nil, fmt.Errorf("oidc discovery issuer %q does not match %q", discovery.Issuer, p.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc discovery document is missing endpoints")
+	}
+
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// key returns the provider key for kid, downloading the key set again when
+// the kid is unknown because the provider may have rotated its keys.
+func (p *Provider) key(kid string) (interface{}, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	if time.Since(p.keysFetchedAt) < keyRefreshInterval {
+		return nil, fmt.Errorf("unknown id token key %q", kid)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, discovery.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var keySet model.JSONWebKeySet
+	status, err := p.doJSON(req, &keySet)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("oidc jwks returned %d", status)
+	}
+
+	p.keys = publicKeys(keySet)
+	p.keysFetchedAt = time.Now()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown id token key %q", kid)
+}
+
+func (p *Provider) doJSON(req *http.Request, v interface{}) (int, error) {
+	res, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return 0, err
+	}
+
+	err = json.Unmarshal(body, v)
+	if err != nil {
+		return res.StatusCode, fmt.Errorf("decode %s: %w", req.URL.Path, err)
+	}
+	return res.StatusCode, nil
+}
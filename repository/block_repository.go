@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"finalProject/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IBlockRepository interface {
+	Add(newBlock model.Block) error
+	Delete(blockerID string, blockedID string) error
+	IsBlocked(userID string, otherID string) (bool, error)
+	FindBlockedUserIDs(userID string) ([]string, error)
+	FindBlocked(userID string, offset int, limit int) ([]model.UserRelationResponse, error)
+	CountBlocked(userID string) (int64, error)
+}
+
+type BlockRepository struct {
+	db *gorm.DB
+}
+
+func NewBlockRepository(db *gorm.DB) *BlockRepository {
+	return &BlockRepository{
+		db: db,
+	}
+}
+
+// Add blocks and removes the follows and follow requests between the two
+// users in both directions, along with their photos in each other's feed.
+func (br *BlockRepository) Add(newBlock model.Block) error {
+	return br.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&newBlock).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("(follower_id = ? AND followee_id = ?) OR (follower_id = ? AND followee_id = ?)",
+			newBlock.BlockerID, newBlock.BlockedID, newBlock.BlockedID, newBlock.BlockerID).
+			Delete(&model.Follow{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("(requester_id = ? AND target_id = ?) OR (requester_id = ? AND target_id = ?)",
+			newBlock.BlockerID, newBlock.BlockedID, newBlock.BlockedID, newBlock.BlockerID).
+			Delete(&model.FollowRequest{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("(user_id = ? AND author_id = ?) OR (user_id = ? AND author_id = ?)",
+			newBlock.BlockerID, newBlock.BlockedID, newBlock.BlockedID, newBlock.BlockerID).
+			Delete(&model.FeedItem{}).Error
+	})
+}
+
+func (br *BlockRepository) Delete(blockerID string, blockedID string) error {
+	tx := br.db.Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Delete(&model.Block{})
+	return tx.Error
+}
+
+// IsBlocked reports whether either user blocked the other.
+func (br *BlockRepository) IsBlocked(userID string, otherID string) (bool, error) {
+	var count int64
+
+	tx := br.db.Model(&model.Block{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", userID, otherID, otherID, userID).
+		Count(&count)
+	return count > 0, tx.Error
+}
+
+// FindBlockedUserIDs returns the users the user blocked and the users who
+// blocked the user.
+func (br *BlockRepository) FindBlockedUserIDs(userID string) ([]string, error) {
+	blocks := []model.Block{}
+
+	tx := br.db.Where("blocker_id = ? OR blocked_id = ?", userID, userID).Find(&blocks)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	userIDs := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		if block.BlockerID == userID {
+			userIDs = append(userIDs, block.BlockedID)
+		} else {
+			userIDs = append(userIDs, block.BlockerID)
+		}
+	}
+	return userIDs, nil
+}
+
+// FindBlocked lists the users the user blocked, latest first.
+func (br *BlockRepository) FindBlocked(userID string, offset int, limit int) ([]model.UserRelationResponse, error) {
+	users := []model.UserRelationResponse{}
+
+	tx := br.db.Model(&model.Block{}).
+		Select("users.id, users.username, blocks.created_at AS since").
+		Joins("JOIN users ON users.id = blocks.blocked_id").
+		Where("blocks.blocker_id = ? AND users.deleted_at IS NULL", userID).
+		Order("blocks.created_at desc, users.id").
+		Offset(offset).
+		Limit(limit).
+		Scan(&users)
+	return users, tx.Error
+}
+
+func (br *BlockRepository) CountBlocked(userID string) (int64, error) {
+	var count int64
+
+	tx := br.db.Model(&model.Block{}).Where("blocker_id = ? AND blocked_id IN (?)", userID, activeUsers(br.db)).Count(&count)
+	return count, tx.Error
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"finalProject/model"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -11,11 +12,18 @@ import (
 
 type ICommentRepository interface {
 	CreateComment(newComment model.Comment) error
-	FindCommentByPhoto(photoID string) ([]model.Comment, error)
-	Get() ([]model.Comment, error)
+	FindCommentByPhoto(photoID string, viewerID string) ([]model.Comment, error)
+	Get(viewerID string, excludedUserIDs []string, includeMature bool) ([]model.Comment, error)
+	CountByUser(userID string) (int64, error)
+	FindByUser(userID string) ([]model.Comment, error)
+	FindReceivedByUser(userID string) ([]model.Comment, error)
 	GetOne(CommentID string) (model.Comment, error)
 	Update(UpdateComment model.Comment, CommentID string) (model.Comment, error)
-	Delete(commentID string) error
+	Delete(commentID string, deletedBy string) error
+	GetDeleted(commentID string, since time.Time) (model.Comment, error)
+	FindDeleted(userID string, since time.Time) ([]model.Comment, error)
+	Restore(commentID string) error
+	PurgeDeleted(before time.Time) error
 }
 
 type CommentRepository struct {
@@ -33,10 +41,12 @@ func (cr *CommentRepository) CreateComment(newComment model.Comment) error {
 	return tx.Error
 }
 
-func (cr *CommentRepository) FindCommentByPhoto(photoID string) ([]model.Comment, error) {
+// FindCommentByPhoto leaves out comments by private accounts the viewer may
+// not see.
+func (cr *CommentRepository) FindCommentByPhoto(photoID string, viewerID string) ([]model.Comment, error) {
 	comments := []model.Comment{}
 
-	err := cr.db.Where("photo_id = ?", photoID).Find(&comments).Error
+	err := cr.db.Where("photo_id = ? AND user_id NOT IN (?)", photoID, privateUsersHiddenFrom(cr.db, viewerID)).Find(&comments).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return []model.Comment{}, model.ErrorNotFound
@@ -51,13 +61,53 @@ func (cr *CommentRepository) FindCommentByPhoto(photoID string) ([]model.Comment
 	return comments, nil
 }
 
-func (cr *CommentRepository) Get() ([]model.Comment, error) {
+// Get lists every comment the viewer may see except those written by
+// excludedUserIDs or left on their photos, and comments on mature photos
+// unless includeMature.
+func (cr *CommentRepository) Get(viewerID string, excludedUserIDs []string, includeMature bool) ([]model.Comment, error) {
 	GetComment := []model.Comment{}
 
-	tx := cr.db.Find(&GetComment)
+	hiddenPrivate := privateUsersHiddenFrom(cr.db, viewerID)
+	privatePhotos := cr.db.Model(&model.Photo{}).Select("photo_id").Where("user_id IN (?)", hiddenPrivate)
+	query := cr.db.Where("user_id NOT IN (?) AND photo_id NOT IN (?)", hiddenPrivate, privatePhotos)
+	if len(excludedUserIDs) > 0 {
+		excludedPhotos := cr.db.Model(&model.Photo{}).Select("photo_id").Where("user_id IN ?", excludedUserIDs)
+		query = query.Where("user_id NOT IN ? AND photo_id NOT IN (?)", excludedUserIDs, excludedPhotos)
+	}
+	if !includeMature {
+		maturePhotos := cr.db.Model(&model.Photo{}).Select("photo_id").Where("content_rating = ?", model.ContentRatingMature)
+		query = query.Where("photo_id NOT IN (?)", maturePhotos)
+	}
+
+	tx := query.Find(&GetComment)
 	return GetComment, tx.Error
 }
 
+func (cr *CommentRepository) CountByUser(userID string) (int64, error) {
+	var count int64
+
+	tx := cr.db.Model(&model.Comment{}).Where("user_id = ?", userID).Count(&count)
+	return count, tx.Error
+}
+
+// FindByUser lists every comment the user wrote, oldest first.
+func (cr *CommentRepository) FindByUser(userID string) ([]model.Comment, error) {
+	comments := []model.Comment{}
+
+	tx := cr.db.Where("user_id = ?", userID).Order("created_at").Find(&comments)
+	return comments, tx.Error
+}
+
+// FindReceivedByUser lists the comments others left on the user's photos,
+// oldest first.
+func (cr *CommentRepository) FindReceivedByUser(userID string) ([]model.Comment, error) {
+	comments := []model.Comment{}
+
+	photoIDs := cr.db.Model(&model.Photo{}).Select("photo_id").Where("user_id = ?", userID)
+	tx := cr.db.Where("photo_id IN (?) AND user_id <> ?", photoIDs, userID).Order("created_at").Find(&comments)
+	return comments, tx.Error
+}
+
 func (cr *CommentRepository) GetOne(CommentID string) (model.Comment, error) {
 	getComment := model.Comment{}
 
@@ -80,13 +130,59 @@ func (cr *CommentRepository) Update(UpdateComment model.Comment, CommentID strin
 	return UpdateComment, err.Error
 }
 
-func (cr *CommentRepository) Delete(commentID string) error {
-	deleteComment := model.Comment{}
-
-	err := cr.db.Delete(&deleteComment, "comment_id = ? ", commentID)
+// Delete moves the comment to the trash.
+func (cr *CommentRepository) Delete(commentID string, deletedBy string) error {
+	err := cr.db.Model(&model.Comment{}).Where("comment_id = ?", commentID).UpdateColumns(map[string]interface{}{
+		"deleted_at": time.Now(),
+		"deleted_by": deletedBy,
+	})
 	if err.Error != nil {
 		return err.Error
 	}
 
 	return nil
 }
+
+// GetDeleted returns a comment in the trash that was deleted after since.
+func (cr *CommentRepository) GetDeleted(commentID string, since time.Time) (model.Comment, error) {
+	comment := model.Comment{}
+
+	err := cr.db.Unscoped().Where("comment_id = ? AND deleted_at > ?", commentID, since).Take(&comment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Comment{}, model.ErrorNotFound
+	}
+	return comment, err
+}
+
+// FindDeleted lists the comments the user deleted after since, latest
+// deletion first. Comments on a photo in the trash are left out, they come
+// back with the photo, and so are comments removed by a moderator.
+func (cr *CommentRepository) FindDeleted(userID string, since time.Time) ([]model.Comment, error) {
+	comments := []model.Comment{}
+
+	photoIDs := cr.db.Model(&model.Photo{}).Select("photo_id")
+	tx := cr.db.Unscoped().
+		Where("user_id = ? AND deleted_by = ? AND deleted_at > ? AND photo_id IN (?)", userID, userID, since, photoIDs).
+		Order("deleted_at desc").
+		Find(&comments)
+	return comments, tx.Error
+}
+
+func (cr *CommentRepository) Restore(commentID string) error {
+	tx := cr.db.Unscoped().Model(&model.Comment{}).
+		Where("comment_id = ? AND deleted_at IS NOT NULL", commentID).
+		UpdateColumns(map[string]interface{}{"deleted_at": nil, "deleted_by": ""})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return model.ErrorNotFound
+	}
+	return nil
+}
+
+// PurgeDeleted removes for good the comments deleted before before.
+func (cr *CommentRepository) PurgeDeleted(before time.Time) error {
+	tx := cr.db.Unscoped().Where("deleted_at < ?", before).Delete(&model.Comment{})
+	return tx.Error
+}
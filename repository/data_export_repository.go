@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"errors"
+	"finalProject/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IDataExportRepository interface {
+	Add(newExport model.DataExport) error
+	GetByID(exportID string) (model.DataExport, error)
+	FindActiveByUser(userID string) (model.DataExport, error)
+	ClaimNext(staleBefore time.Time) (model.DataExport, error)
+	Complete(exportID string, filePath string, completedAt time.Time, expiresAt time.Time) error
+	Fail(exportID string) error
+	FindExpired(now time.Time) ([]model.DataExport, error)
+	MarkExpired(exportID string) error
+}
+
+type DataExportRepository struct {
+	db *gorm.DB
+}
+
+func NewDataExportRepository(db *gorm.DB) *DataExportRepository {
+	return &DataExportRepository{
+		db: db,
+	}
+}
+
+func (der *DataExportRepository) Add(newExport model.DataExport) error {
+	tx := der.db.Create(&newExport)
+	return tx.Error
+}
+
+func (der *DataExportRepository) GetByID(exportID string) (model.DataExport, error) {
+	var export model.DataExport
+	err := der.db.Where("id = ?", exportID).Take(&export).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.DataExport{}, model.ErrorNotFound
+	}
+	return export, err
+}
+
+// FindActiveByUser returns the pending or processing export of the user.
+func (der *DataExportRepository) FindActiveByUser(userID string) (model.DataExport, error) {
+	var export model.DataExport
+	err := der.db.Where("user_id = ? AND status IN ?", userID, []string{model.DataExportPending, model.DataExportProcessing}).
+		Order("created_at desc").
+		Take(&export).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.DataExport{}, model.ErrorNotFound
+	}
+	return export, err
+}
+
+// ClaimNext marks the oldest pending export as processing and returns it.
+// Exports left processing since before staleBefore, by a server that stopped
+// halfway, are claimed again. The conditional update keeps two servers from
+// claiming the same export. ErrorNotFound means there is nothing to do.
+func (der *DataExportRepository) ClaimNext(staleBefore time.Time) (model.DataExport, error) {
+	claimable := "status = ? OR (status = ? AND started_at < ?)"
+
+	for {
+		var export model.DataExport
+		err := der.db.Where(claimable, model.DataExportPending, model.DataExportProcessing, staleBefore).
+			Order("created_at").
+			Take(&export).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.DataExport{}, model.ErrorNotFound
+		}
+		if err != nil {
+			return model.DataExport{}, err
+		}
+
+		now := time.Now()
+		tx := der.db.Model(&model.DataExport{}).
+			Where("id = ?", export.ID).
+			Where(claimable, model.DataExportPending, model.DataExportProcessing, staleBefore).
+			Updates(map[string]interface{}{"status": model.DataExportProcessing, "started_at": now})
+		if tx.Error != nil {
+			return model.DataExport{}, tx.Error
+		}
+		if tx.RowsAffected == 1 {
+			export.Status = model.DataExportProcessing
+			export.StartedAt = &now
+			return export, nil
+		}
+	}
+}
+
+func (der *DataExportRepository) Complete(exportID string, filePath string, completedAt time.Time, expiresAt time.Time) error {
+	tx := der.db.Model(&model.DataExport{}).Where("id = ?", exportID).Updates(map[string]interface{}{
+		"status":       model.DataExportReady,
+		"file_path":    filePath,
+		"completed_at": completedAt,
+		"expires_at":   expiresAt,
+	})
+	return tx.Error
+}
+
+func (der *DataExportRepository) Fail(exportID string) error {
+	tx := der.db.Model(&model.DataExport{}).Where("id = ?", exportID).Update("status", model.DataExportFailed)
+	return tx.Error
+}
+
+// FindExpired lists the ready exports whose link expired or whose user no
+// longer exists, their archives are due for removal.
+func (der *DataExportRepository) FindExpired(now time.Time) ([]model.DataExport, error) {
+	exports := []model.DataExport{}
+
+	userIDs := der.db.Model(&model.User{}).Select("id")
+	tx := der.db.Where("status = ? AND (expires_at < ? OR user_id NOT IN (?))", model.DataExportReady, now, userIDs).Find(&exports)
+	return exports, tx.Error
+}
+
+func (der *DataExportRepository) MarkExpired(exportID string) error {
+	tx := der.db.Model(&model.DataExport{}).Where("id = ?", exportID).Updates(map[string]interface{}{
+		"status":    model.DataExportExpired,
+		"file_path": "",
+	})
+	return tx.Error
+}
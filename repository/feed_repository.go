@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"finalProject/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IFeedRepository interface {
+	FanOut(photoID string) error
+	Backfill(userID string, authorID string, limit int) error
+	RemoveAuthor(userID string, authorID string) error
+	Find(userID string, cursor *model.FeedCursor, excludedUserIDs []string, limit int, includeMature bool) ([]model.Photo, error)
+	AddPullAuthor(userID string) error
+	FindPullAuthors(userID string) ([]string, error)
+}
+
+type FeedRepository struct {
+	db *gorm.DB
+}
+
+func NewFeedRepository(db *gorm.DB) *FeedRepository {
+	return &FeedRepository{
+		db: db,
+	}
+}
+
+// FanOut puts the photo in the feed of every follower of the user who posted
+// it, in one statement.
+func (fr *FeedRepository) FanOut(photoID string) error {
+	tx := fr.db.Exec(`INSERT INTO feed_items (user_id, photo_id, author_id, created_at)
+		SELECT follows.follower_id, photos.photo_id, photos.user_id, photos.created_at FROM photos
+		JOIN follows ON follows.followee_id = photos.user_id
+		WHERE photos.photo_id = ?
+		ON CONFLICT DO NOTHING`, photoID)
+	return tx.Error
+}
+
+// Backfill puts the latest photos of the author in the feed of the user, used
+// when the user starts following the author.
+func (fr *FeedRepository) Backfill(userID string, authorID string, limit int) error {
+	tx := fr.db.Exec(`INSERT INTO feed_items (user_id, photo_id, author_id, created_at)
+		SELECT ?, photo_id, user_id, created_at FROM photos WHERE user_id = ? AND deleted_at IS NULL
+		ORDER BY created_at desc LIMIT ?
+		ON CONFLICT DO NOTHING`, userID, authorID, limit)
+	return tx.Error
+}
+
+func (fr *FeedRepository) RemoveAuthor(userID string, authorID string) error {
+	tx := fr.db.Where("user_id = ? AND author_id = ?", userID, authorID).Delete(&model.FeedItem{})
+	return tx.Error
+}
+
+// Find reads a page of the feed of the user, newest first, starting after the
+// cursor when there is one. Mature photos are left out unless includeMature.
+func (fr *FeedRepository) Find(userID string, cursor *model.FeedCursor, excludedUserIDs []string, limit int, includeMature bool) ([]model.Photo, error) {
+	photos := []model.Photo{}
+
+	query := fr.db.Model(&model.Photo{}).
+		Select("photos.*").
+		Joins("JOIN feed_items ON feed_items.photo_id = photos.photo_id").
+		Where("feed_items.user_id = ?", userID)
+	if cursor != nil {
+		query = query.Where("(feed_items.created_at, feed_items.photo_id) < (?, ?)", cursor.CreatedAt, cursor.PhotoID)
+	}
+	if len(excludedUserIDs) > 0 {
+		query = query.Where("feed_items.author_id NOT IN ?", excludedUserIDs)
+	}
+	if !includeMature {
+		query = query.Where("photos.content_rating <> ?", model.ContentRatingMature)
+	}
+
+	tx := query.Order("feed_items.created_at desc, feed_items.photo_id desc").Limit(limit).Find(&photos)
+	return photos, tx.Error
+}
+
+func (fr *FeedRepository) AddPullAuthor(userID string) error {
+	tx := fr.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.FeedPullAuthor{UserID: userID})
+	return tx.Error
+}
+
+// FindPullAuthors returns the users followed by the user whose photos are not
+// fanned out.
+func (fr *FeedRepository) FindPullAuthors(userID string) ([]string, error) {
+	userIDs := []string{}
+
+	tx := fr.db.Model(&model.Follow{}).
+		Joins("JOIN feed_pull_authors ON feed_pull_authors.user_id = follows.followee_id").
+		Where("follows.follower_id = ?", userID).
+		Pluck("follows.followee_id", &userIDs)
+	return userIDs, tx.Error
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"finalProject/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IFollowRepository interface {
+	Add(newFollow model.Follow) error
+	Delete(followerID string, followeeID string) error
+	FindFollowers(userID string, offset int, limit int) ([]model.FollowUserResponse, error)
+	FindFollowing(userID string, offset int, limit int) ([]model.FollowUserResponse, error)
+	CountFollowers(userID string) (int64, error)
+	CountFollowing(userID string) (int64, error)
+	IsFollowing(followerID string, followeeID string) (bool, error)
+	IsPrivateTo(userID string, viewerID string) (bool, error)
+}
+
+type FollowRepository struct {
+	db *gorm.DB
+}
+
+func NewFollowRepository(db *gorm.DB) *FollowRepository {
+	return &FollowRepository{
+		db: db,
+	}
+}
+
+// Add keeps the original follow time when the follow already exists.
+func (fr *FollowRepository) Add(newFollow model.Follow) error {
+	tx := fr.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&newFollow)
+	return tx.Error
+}
+
+func (fr *FollowRepository) Delete(followerID string, followeeID string) error {
+	tx := fr.db.Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&model.Follow{})
+	return tx.Error
+}
+
+// FindFollowers lists who follows the user, latest follow first.
+func (fr *FollowRepository) FindFollowers(userID string, offset int, limit int) ([]model.FollowUserResponse, error) {
+	return fr.findUsers("follower_id", "followee_id", userID, offset, limit)
+}
+
+// FindFollowing lists who the user follows, latest follow first.
+func (fr *FollowRepository) FindFollowing(userID string, offset int, limit int) ([]model.FollowUserResponse, error) {
+	return fr.findUsers("followee_id", "follower_id", userID, offset, limit)
+}
+
+func (fr *FollowRepository) findUsers(listed string, filtered string, userID string, offset int, limit int) ([]model.FollowUserResponse, error) {
+	users := []model.FollowUserResponse{}
+
+	tx := fr.db.Model(&model.Follow{}).
+		Select("users.id, users.username, follows.created_at AS followed_at").
+		Joins("JOIN users ON users.id = follows."+listed).
+		Where("follows."+filtered+" = ? AND users.deleted_at IS NULL", userID).
+		Order("follows.created_at desc, users.id").
+		Offset(offset).
+		Limit(limit).
+		Scan(&users)
+	return users, tx.Error
+}
+
+func (fr *FollowRepository) CountFollowers(userID string) (int64, error) {
+	var count int64
+
+	tx := fr.db.Model(&model.Follow{}).Where("followee_id = ? AND follower_id IN (?)", userID, activeUsers(fr.db)).Count(&count)
+	return count, tx.Error
+}
+
+func (fr *FollowRepository) CountFollowing(userID string) (int64, error) {
+	var count int64
+
+	tx := fr.db.Model(&model.Follow{}).Where("follower_id = ? AND followee_id IN (?)", userID, activeUsers(fr.db)).Count(&count)
+	return count, tx.Error
+}
+
+func (fr *FollowRepository) IsFollowing(followerID string, followeeID string) (bool, error) {
+	var count int64
+
+	tx := fr.db.Model(&model.Follow{}).Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Count(&count)
+	return count > 0, tx.Error
+}
+
+// IsPrivateTo reports whether the user has a private account the viewer is
+// not an approved follower of. An unknown user is not private.
+func (fr *FollowRepository) IsPrivateTo(userID string, viewerID string) (bool, error) {
+	var count int64
+
+	tx := fr.db.Model(&model.User{}).
+		Where("id = ? AND id IN (?)", userID, privateUsersHiddenFrom(fr.db, viewerID)).
+		Count(&count)
+	return count > 0, tx.Error
+}
+
+// privateUsersHiddenFrom selects the ids of the private accounts whose
+// photos, comments and profile the viewer may not see: every private account
+// except the viewer's own and those the viewer follows.
+func privateUsersHiddenFrom(db *gorm.DB, viewerID string) *gorm.DB {
+	following := db.Model(&model.Follow{}).Select("followee_id").Where("follower_id = ?", viewerID)
+	return db.Model(&model.User{}).Select("id").Where("is_private AND id <> ? AND id NOT IN (?)", viewerID, following)
+}
+
+// activeUsers selects the ids of the users that are not in the trash.
+func activeUsers(db *gorm.DB) *gorm.DB {
+	return db.Model(&model.User{}).Select("id")
+}
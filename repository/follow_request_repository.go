@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"finalProject/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IFollowRequestRepository interface {
+	Add(newRequest model.FollowRequest) error
+	Delete(requesterID string, targetID string) error
+	Accept(requesterID string, targetID string) error
+	AcceptAll(targetID string) ([]string, error)
+	Reject(requesterID string, targetID string) error
+	FindPending(targetID string, offset int, limit int) ([]model.UserRelationResponse, error)
+	CountPending(targetID string) (int64, error)
+}
+
+type FollowRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewFollowRequestRepository(db *gorm.DB) *FollowRequestRepository {
+	return &FollowRequestRepository{
+		db: db,
+	}
+}
+
+// Add keeps the original request time when the request is already pending.
+func (frr *FollowRequestRepository) Add(newRequest model.FollowRequest) error {
+	tx := frr.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&newRequest)
+	return tx.Error
+}
+
+func (frr *FollowRequestRepository) Delete(requesterID string, targetID string) error {
+	tx := frr.db.Where("requester_id = ? AND target_id = ?", requesterID, targetID).Delete(&model.FollowRequest{})
+	return tx.Error
+}
+
+// Accept turns the pending request into a follow, ErrorNotFound means there
+// was no pending request.
+func (frr *FollowRequestRepository) Accept(requesterID string, targetID string) error {
+	return frr.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("requester_id = ? AND target_id = ?", requesterID, targetID).Delete(&model.FollowRequest{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrorNotFound
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.Follow{
+			FollowerID: requesterID,
+			FolloweeID: targetID,
+		}).Error
+	})
+}
+
+// AcceptAll turns every pending request of the target into a follow and
+// returns the requesters, used when the account stops being private.
+func (frr *FollowRequestRepository) AcceptAll(targetID string) ([]string, error) {
+	requesterIDs := []string{}
+
+	err := frr.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.FollowRequest{}).Where("target_id = ?", targetID).Pluck("requester_id", &requesterIDs).Error
+		if err != nil || len(requesterIDs) == 0 {
+			return err
+		}
+
+		follows := make([]model.Follow, 0, len(requesterIDs))
+		now := time.Now()
+		for _, requesterID := range requesterIDs {
+			follows = append(follows, model.Follow{
+				FollowerID: requesterID,
+				FolloweeID: targetID,
+				CreatedAt:  now,
+			})
+		}
+
+		err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&follows).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("target_id = ? AND requester_id IN ?", targetID, requesterIDs).Delete(&model.FollowRequest{}).Error
+	})
+	return requesterIDs, err
+}
+
+// Reject drops the pending request, ErrorNotFound means there was none.
+func (frr *FollowRequestRepository) Reject(requesterID string, targetID string) error {
+	tx := frr.db.Where("requester_id = ? AND target_id = ?", requesterID, targetID).Delete(&model.FollowRequest{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return model.ErrorNotFound
+	}
+	return nil
+}
+
+// FindPending lists who asked to follow the target, latest request first.
+func (frr *FollowRequestRepository) FindPending(targetID string, offset int, limit int) ([]model.UserRelationResponse, error) {
+	users := []model.UserRelationResponse{}
+
+	tx := frr.db.Model(&model.FollowRequest{}).
+		Select("users.id, users.username, follow_requests.created_at AS since").
+		Joins("JOIN users ON users.id = follow_requests.requester_id").
+		Where("follow_requests.target_id = ?", targetID).
+		Order("follow_requests.created_at desc, users.id").
+		Offset(offset).
+		Limit(limit).
+		Scan(&users)
+	return users, tx.Error
+}
+
+func (frr *FollowRequestRepository) CountPending(targetID string) (int64, error) {
+	var count int64
+
+	tx := frr.db.Model(&model.FollowRequest{}).Where("target_id = ?", targetID).Count(&count)
+	return count, tx.Error
+}
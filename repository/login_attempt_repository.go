@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"finalProject/model"
+	"sync"
+	"time"
+)
+
+type ILoginAttemptRepository interface {
+	Get(key string) (model.LoginAttempt, error)
+	RecordFailure(key string, window time.Duration) (model.LoginAttempt, error)
+	Lock(key string, until time.Time) error
+	Reset(key string) error
+}
+
+// InMemoryLoginAttemptRepository keeps failed attempts in process memory, so
+// counters are per instance and start from zero after a restart.
+type InMemoryLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]model.LoginAttempt
+	writes   int
+}
+
+func NewInMemoryLoginAttemptRepository() *InMemoryLoginAttemptRepository {
+	return &InMemoryLoginAttemptRepository{
+		attempts: map[string]model.LoginAttempt{},
+	}
+}
+
+func (mr *InMemoryLoginAttemptRepository) Get(key string) (model.LoginAttempt, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	attempt, ok := mr.attempts[key]
+	if !ok {
+		return model.LoginAttempt{Key: key}, nil
+	}
+	return attempt, nil
+}
+
+// RecordFailure adds one failure. Failures older than window are forgotten
+// first, so old mistakes do not count towards a lockout forever.
+func (mr *InMemoryLoginAttemptRepository) RecordFailure(key string, window time.Duration) (model.LoginAttempt, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	now := time.Now()
+
+	mr.writes++
+	if mr.writes%1000 == 0 {
+		for k, attempt := range mr.attempts {
+			if now.Sub(attempt.LastFailure) > window && now.After(attempt.LockedUntil) {
+				delete(mr.attempts, k)
+			}
+		}
+	}
+
+	attempt, ok := mr.attempts[key]
+	if !ok || (now.Sub(attempt.LastFailure) > window && now.After(attempt.LockedUntil)) {
+		attempt = model.LoginAttempt{Key: key}
+	}
+
+	attempt.Failures++
+	attempt.LastFailure = now
+	mr.attempts[key] = attempt
+
+	return attempt, nil
+}
+
+func (mr *InMemoryLoginAttemptRepository) Lock(key string, until time.Time) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	attempt, ok := mr.attempts[key]
+	if !ok {
+		attempt = model.LoginAttempt{Key: key}
+	}
+	attempt.LockedUntil = until
+	mr.attempts[key] = attempt
+
+	return nil
+}
+
+func (mr *InMemoryLoginAttemptRepository) Reset(key string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	delete(mr.attempts, key)
+	return nil
+}
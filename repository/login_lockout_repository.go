@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"finalProject/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ILoginLockoutRepository interface {
+	Add(lockout model.LoginLockout) error
+	MarkUnlocked(key string) error
+}
+
+type LoginLockoutRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginLockoutRepository(db *gorm.DB) *LoginLockoutRepository {
+	return &LoginLockoutRepository{
+		db: db,
+	}
+}
+
+func (lr *LoginLockoutRepository) Add(lockout model.LoginLockout) error {
+	tx := lr.db.Create(&lockout)
+	return tx.Error
+}
+
+// MarkUnlocked records that a still running lockout was lifted early.
+func (lr *LoginLockoutRepository) MarkUnlocked(key string) error {
+	now := time.Now()
+	tx := lr.db.Model(&model.LoginLockout{}).
+		Where("key = ? AND unlocked_at IS NULL AND locked_until > ?", key, now).
+		Update("unlocked_at", now)
+	return tx.Error
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"finalProject/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IMuteRepository interface {
+	Add(newMute model.Mute) error
+	Delete(muterID string, mutedID string) error
+	FindMutedUserIDs(userID string) ([]string, error)
+	FindMuted(userID string, offset int, limit int) ([]model.UserRelationResponse, error)
+	CountMuted(userID string) (int64, error)
+}
+
+type MuteRepository struct {
+	db *gorm.DB
+}
+
+func NewMuteRepository(db *gorm.DB) *MuteRepository {
+	return &MuteRepository{
+		db: db,
+	}
+}
+
+func (mr *MuteRepository) Add(newMute model.Mute) error {
+	tx := mr.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&newMute)
+	return tx.Error
+}
+
+func (mr *MuteRepository) Delete(muterID string, mutedID string) error {
+	tx := mr.db.Where("muter_id = ? AND muted_id = ?", muterID, mutedID).Delete(&model.Mute{})
+	return tx.Error
+}
+
+func (mr *MuteRepository) FindMutedUserIDs(userID string) ([]string, error) {
+	userIDs := []string{}
+
+	tx := mr.db.Model(&model.Mute{}).Where("muter_id = ?", userID).Pluck("muted_id", &userIDs)
+	return userIDs, tx.Error
+}
+
+// FindMuted lists the users the user muted, latest first.
+func (mr *MuteRepository) FindMuted(userID string, offset int, limit int) ([]model.UserRelationResponse, error) {
+	users := []model.UserRelationResponse{}
+
+	tx := mr.db.Model(&model.Mute{}).
+		Select("users.id, users.username, mutes.created_at AS since").
+		Joins("JOIN users ON users.id = mutes.muted_id").
+		Where("mutes.muter_id = ? AND users.deleted_at IS NULL", userID).
+		Order("mutes.created_at desc, users.id").
+		Offset(offset).
+		Limit(limit).
+		Scan(&users)
+	return users, tx.Error
+}
+
+func (mr *MuteRepository) CountMuted(userID string) (int64, error) {
+	var count int64
+
+	tx := mr.db.Model(&model.Mute{}).Where("muter_id = ? AND muted_id IN (?)", userID, activeUsers(mr.db)).Count(&count)
+	return count, tx.Error
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"finalProject/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IOIDCStateRepository interface {
+	Add(state model.OIDCLoginState) error
+	Consume(stateHash string) (model.OIDCLoginState, error)
+}
+
+type OIDCStateRepository struct {
+	db *gorm.DB
+}
+
+func NewOIDCStateRepository(db *gorm.DB) *OIDCStateRepository {
+	return &OIDCStateRepository{
+		db: db,
+	}
+}
+
+// Add stores a new state and clears the ones that expired without a callback.
+func (sr *OIDCStateRepository) Add(state model.OIDCLoginState) error {
+	err := sr.db.Where("expires_at < ?", time.Now()).Delete(&model.OIDCLoginState{}).Error
+	if err != nil {
+		return err
+	}
+
+	tx := sr.db.Create(&state)
+	return tx.Error
+}
+
+// Consume deletes and returns the state, so a callback cannot be replayed.
+func (sr *OIDCStateRepository) Consume(stateHash string) (model.OIDCLoginState, error) {
+	state := model.OIDCLoginState{}
+
+	tx := sr.db.Clauses(clause.Returning{}).Where("state_hash = ?", stateHash).Delete(&state)
+	if tx.Error != nil {
+		return model.OIDCLoginState{}, tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return model.OIDCLoginState{}, model.ErrorNotFound
+	}
+
+	return state, nil
+}
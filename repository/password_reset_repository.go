@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"errors"
+	"finalProject/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IPasswordResetRepository interface {
+	Add(newReset model.PasswordReset) error
+	GetByHash(tokenHash string) (model.PasswordReset, error)
+	MarkUsed(resetID string) (bool, error)
+	InvalidateForUser(userID string) error
+}
+
+type PasswordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		db: db,
+	}
+}
+
+func (pr *PasswordResetRepository) Add(newReset model.PasswordReset) error {
+	tx := pr.db.Create(&newReset)
+	return tx.Error
+}
+
+func (pr *PasswordResetRepository) GetByHash(tokenHash string) (model.PasswordReset, error) {
+	reset := model.PasswordReset{}
+
+	err := pr.db.Where("token_hash = ?", tokenHash).Take(&reset).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.PasswordReset{}, model.ErrorNotFound
+	}
+
+	return reset, err
+}
+
+// MarkUsed consumes the reset token, it reports false when it was already used.
+func (pr *PasswordResetRepository) MarkUsed(resetID string) (bool, error) {
+	tx := pr.db.Model(&model.PasswordReset{}).
+		Where("id = ? AND used_at IS NULL", resetID).
+		Update("used_at", time.Now())
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	return tx.RowsAffected == 1, nil
+}
+
+func (pr *PasswordResetRepository) InvalidateForUser(userID string) error {
+	tx := pr.db.Model(&model.PasswordReset{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now())
+	return tx.Error
+}
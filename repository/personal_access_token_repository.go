@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"errors"
+	"finalProject/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// lastUsedPrecision limits how often a busy token writes its last use.
+const lastUsedPrecision = time.Minute
+
+type IPersonalAccessTokenRepository interface {
+	Add(newToken model.PersonalAccessToken) error
+	GetByHash(tokenHash string) (model.PersonalAccessToken, error)
+	FindByUser(userID string) ([]model.PersonalAccessToken, error)
+	Revoke(tokenID string, userID string) error
+	RevokeAllForUser(userID string) error
+	TouchLastUsed(tokenID string) error
+}
+
+type PersonalAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPersonalAccessTokenRepository(db *gorm.DB) *PersonalAccessTokenRepository {
+	return &PersonalAccessTokenRepository{
+		db: db,
+	}
+}
+
+func (pr *PersonalAccessTokenRepository) Add(newToken model.PersonalAccessToken) error {
+	tx := pr.db.Create(&newToken)
+	return tx.Error
+}
+
+func (pr *PersonalAccessTokenRepository) GetByHash(tokenHash string) (model.PersonalAccessToken, error) {
+	token := model.PersonalAccessToken{}
+
+	err := pr.db.Where("token_hash = ?", tokenHash).Take(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.PersonalAccessToken{}, model.ErrorNotFound
+	}
+
+	return token, err
+}
+
+// FindByUser lists the tokens that were not revoked, newest first.
+func (pr *PersonalAccessTokenRepository) FindByUser(userID string) ([]model.PersonalAccessToken, error) {
+	tokens := []model.PersonalAccessToken{}
+
+	tx := pr.db.Where("user_id = ? AND revoked_at IS NULL", userID).Order("created_at desc").Find(&tokens)
+	return tokens, tx.Error
+}
+
+func (pr *PersonalAccessTokenRepository) Revoke(tokenID string, userID string) error {
+	tx := pr.db.Model(&model.PersonalAccessToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", tokenID, userID).
+		Update("revoked_at", time.Now())
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return model.ErrorNotFound
+	}
+	return nil
+}
+
+func (pr *PersonalAccessTokenRepository) RevokeAllForUser(userID string) error {
+	tx := pr.db.Model(&model.PersonalAccessToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	return tx.Error
+}
+
+func (pr *PersonalAccessTokenRepository) TouchLastUsed(tokenID string) error {
+	now := time.Now()
+	tx := pr.db.Model(&model.PersonalAccessToken{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", tokenID, now.Add(-lastUsedPrecision)).
+		Update("last_used_at", now)
+	return tx.Error
+}
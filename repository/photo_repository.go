@@ -2,6 +2,7 @@ package repository
 
 import (
 	"finalProject/model"
+	"time"
 
 	"errors"
 
@@ -11,10 +12,18 @@ import (
 
 type IPhotoRepository interface {
 	Add(newPhoto model.Photo) error
-	FindAll() ([]model.Photo, error)
+	FindAll(viewerID string, excludedUserIDs []string, includeMature bool) ([]model.Photo, error)
+	FindLatestByUser(userID string, limit int, includeMature bool) ([]model.Photo, error)
+	CountByUser(userID string) (int64, error)
+	FindByUser(userID string) ([]model.Photo, error)
+	FindByUsers(userIDs []string, cursor *model.FeedCursor, limit int, includeMature bool) ([]model.Photo, error)
 	GetOne(photoID string) (model.Photo, error)
 	PhotoUpdate(request model.Photo, photoID string) (model.Photo, error)
-	DeletePhoto(PhotoId string) error
+	DeletePhoto(PhotoId string, deletedBy string) error
+	GetDeleted(photoID string, since time.Time) (model.Photo, error)
+	FindDeleted(userID string, since time.Time) ([]model.Photo, error)
+	Restore(photoID string, deletedAt time.Time) error
+	PurgeDeleted(before time.Time) error
 }
 
 type PhotoRepository struct {
@@ -32,10 +41,64 @@ func (pr *PhotoRepository) Add(newPhoto model.Photo) error {
 	return tx.Error
 }
 
-func (pr *PhotoRepository) FindAll() ([]model.Photo, error) {
+// FindAll lists every photo the viewer may see except those of
+// excludedUserIDs, and mature photos unless includeMature.
+func (pr *PhotoRepository) FindAll(viewerID string, excludedUserIDs []string, includeMature bool) ([]model.Photo, error) {
 	photos := []model.Photo{}
 
-	tx := pr.db.Find(&photos)
+	query := pr.db.Where("user_id NOT IN (?)", privateUsersHiddenFrom(pr.db, viewerID))
+	if len(excludedUserIDs) > 0 {
+		query = query.Where("user_id NOT IN ?", excludedUserIDs)
+	}
+	if !includeMature {
+		query = query.Where("content_rating <> ?", model.ContentRatingMature)
+	}
+
+	tx := query.Find(&photos)
+	return photos, tx.Error
+}
+
+func (pr *PhotoRepository) FindLatestByUser(userID string, limit int, includeMature bool) ([]model.Photo, error) {
+	photos := []model.Photo{}
+
+	query := pr.db.Where("user_id = ?", userID)
+	if !includeMature {
+		query = query.Where("content_rating <> ?", model.ContentRatingMature)
+	}
+
+	tx := query.Order("created_at desc").Limit(limit).Find(&photos)
+	return photos, tx.Error
+}
+
+func (pr *PhotoRepository) CountByUser(userID string) (int64, error) {
+	var count int64
+
+	tx := pr.db.Model(&model.Photo{}).Where("user_id = ?", userID).Count(&count)
+	return count, tx.Error
+}
+
+// FindByUser lists every photo of the user, oldest first.
+func (pr *PhotoRepository) FindByUser(userID string) ([]model.Photo, error) {
+	photos := []model.Photo{}
+
+	tx := pr.db.Where("user_id = ?", userID).Order("created_at").Find(&photos)
+	return photos, tx.Error
+}
+
+// FindByUsers reads the photos of the users newest first, starting after the
+// cursor when there is one. Mature photos are left out unless includeMature.
+func (pr *PhotoRepository) FindByUsers(userIDs []string, cursor *model.FeedCursor, limit int, includeMature bool) ([]model.Photo, error) {
+	photos := []model.Photo{}
+
+	query := pr.db.Where("user_id IN ?", userIDs)
+	if cursor != nil {
+		query = query.Where("(created_at, photo_id) < (?, ?)", cursor.CreatedAt, cursor.PhotoID)
+	}
+	if !includeMature {
+		query = query.Where("content_rating <> ?", model.ContentRatingMature)
+	}
+
+	tx := query.Order("created_at desc, photo_id desc").Limit(limit).Find(&photos)
 	return photos, tx.Error
 }
 
@@ -55,6 +118,7 @@ func (pr *PhotoRepository) PhotoUpdate(request model.Photo, photoID string) (mod
 		Columns: []clause.Column{
 			{Name: "photo_id"},
 			{Name: "user_id"},
+			{Name: "content_rating"},
 			{Name: "updated_at"},
 		},
 	},
@@ -63,14 +127,84 @@ func (pr *PhotoRepository) PhotoUpdate(request model.Photo, photoID string) (mod
 	return request, err.Error
 }
 
-func (pr *PhotoRepository) DeletePhoto(PhotoId string) error {
-	delPhoto := model.Photo{
-		PhotoID: PhotoId,
-	}
+// DeletePhoto moves the photo to the trash together with its comments. They
+// share the deletion time so Restore can tell them from comments deleted on
+// their own. Feed items stay until the photo is purged.
+func (pr *PhotoRepository) DeletePhoto(PhotoId string, deletedBy string) error {
+	deleted := map[string]interface{}{"deleted_at": time.Now(), "deleted_by": deletedBy}
+
+	return pr.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.Comment{}).Where("photo_id = ?", PhotoId).UpdateColumns(deleted).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&model.Photo{}).Where("photo_id = ?", PhotoId).UpdateColumns(deleted).Error
+	})
+}
 
-	tx := pr.db.Select("Comments").Delete(&delPhoto)
-	if tx.Error != nil {
-		return tx.Error
+// GetDeleted returns a photo in the trash that was deleted after since.
+func (pr *PhotoRepository) GetDeleted(photoID string, since time.Time) (model.Photo, error) {
+	photo := model.Photo{}
+
+	err := pr.db.Unscoped().Where("photo_id = ? AND deleted_at > ?", photoID, since).Take(&photo).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Photo{}, model.ErrorNotFound
 	}
-	return nil
+	return photo, err
+}
+
+// FindDeleted lists the photos the user deleted after since, latest deletion
+// first. Photos removed by a moderator are left out.
+func (pr *PhotoRepository) FindDeleted(userID string, since time.Time) ([]model.Photo, error) {
+	photos := []model.Photo{}
+
+	tx := pr.db.Unscoped().Where("user_id = ? AND deleted_by = ? AND deleted_at > ?", userID, userID, since).Order("deleted_at desc").Find(&photos)
+	return photos, tx.Error
+}
+
+// Restore takes the photo out of the trash with the comments deleted along
+// with it.
+func (pr *PhotoRepository) Restore(photoID string, deletedAt time.Time) error {
+	restored := map[string]interface{}{"deleted_at": nil, "deleted_by": ""}
+
+	return pr.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Model(&model.Comment{}).
+			Where("photo_id = ? AND deleted_at = ?", photoID, deletedAt).
+			UpdateColumns(restored).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Model(&model.Photo{}).
+			Where("photo_id = ? AND deleted_at = ?", photoID, deletedAt).
+			UpdateColumns(restored)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrorNotFound
+		}
+		return nil
+	})
+}
+
+// PurgeDeleted removes for good the photos deleted before before, with all of
+// their comments and feed items.
+func (pr *PhotoRepository) PurgeDeleted(before time.Time) error {
+	return pr.db.Transaction(func(tx *gorm.DB) error {
+		photoIDs := tx.Unscoped().Model(&model.Photo{}).Select("photo_id").Where("deleted_at < ?", before)
+
+		err := tx.Where("photo_id IN (?)", photoIDs).Delete(&model.FeedItem{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Where("photo_id IN (?)", photoIDs).Delete(&model.Comment{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Where("deleted_at < ?", before).Delete(&model.Photo{}).Error
+	})
 }
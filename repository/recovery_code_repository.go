@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"finalProject/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IRecoveryCodeRepository interface {
+	Replace(userID string, codes []model.RecoveryCode) error
+	Use(userID string, codeHash string) (bool, error)
+	DeleteForUser(userID string) error
+}
+
+type RecoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{
+		db: db,
+	}
+}
+
+// Replace drops the user's previous recovery codes and stores the new set.
+func (rr *RecoveryCodeRepository) Replace(userID string, codes []model.RecoveryCode) error {
+	return rr.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&codes).Error
+	})
+}
+
+// Use consumes a recovery code, it reports false when no unused code matches.
+func (rr *RecoveryCodeRepository) Use(userID string, codeHash string) (bool, error) {
+	tx := rr.db.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	return tx.RowsAffected > 0, nil
+}
+
+func (rr *RecoveryCodeRepository) DeleteForUser(userID string) error {
+	tx := rr.db.Where("user_id = ?", userID).Delete(&model.RecoveryCode{})
+	return tx.Error
+}
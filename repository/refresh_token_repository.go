@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"errors"
+	"finalProject/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IRefreshTokenRepository interface {
+	Add(newToken model.RefreshToken) error
+	GetByHash(tokenHash string) (model.RefreshToken, error)
+	MarkUsed(tokenID string) (bool, error)
+	RevokeFamily(familyID string) error
+	RevokeAllForUser(userID string) error
+}
+
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		db: db,
+	}
+}
+
+func (rr *RefreshTokenRepository) Add(newToken model.RefreshToken) error {
+	tx := rr.db.Create(&newToken)
+	return tx.Error
+}
+
+func (rr *RefreshTokenRepository) GetByHash(tokenHash string) (model.RefreshToken, error) {
+	token := model.RefreshToken{}
+
+	err := rr.db.Where("token_hash = ?", tokenHash).Take(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.RefreshToken{}, model.ErrorNotFound
+	}
+
+	return token, err
+}
+
+// MarkUsed flags the token as rotated. It reports false when another request
+// already used or revoked the token, so concurrent refreshes cannot both win.
+func (rr *RefreshTokenRepository) MarkUsed(tokenID string) (bool, error) {
+	tx := rr.db.Model(&model.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", tokenID).
+		Update("used_at", time.Now())
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	return tx.RowsAffected == 1, nil
+}
+
+func (rr *RefreshTokenRepository) RevokeFamily(familyID string) error {
+	tx := rr.db.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now())
+	return tx.Error
+}
+
+func (rr *RefreshTokenRepository) RevokeAllForUser(userID string) error {
+	tx := rr.db.Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	return tx.Error
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"errors"
+	"finalProject/model"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IRevocationRepository interface {
+	Revoke(jti string, userID string, expiresAt time.Time) error
+	RevokeAllForUser(userID string, before time.Time) error
+	IsRevoked(jti string, userID string, issuedAt time.Time) (bool, error)
+}
+
+type RevocationRepository struct {
+	db *gorm.DB
+}
+
+func NewRevocationRepository(db *gorm.DB) *RevocationRepository {
+	return &RevocationRepository{
+		db: db,
+	}
+}
+
+func (rr *RevocationRepository) Revoke(jti string, userID string, expiresAt time.Time) error {
+	err := rr.db.Where("expires_at < ?", time.Now()).Delete(&model.RevokedToken{}).Error
+	if err != nil {
+		return err
+	}
+
+	tx := rr.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.RevokedToken{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	})
+	return tx.Error
+}
+
+// RevokeAllForUser revokes the access tokens of the user issued before the
+// second of before. Tokens carry their issue time in whole seconds, so a token
+// issued within that second, usually by the login that follows, stays valid.
+func (rr *RevocationRepository) RevokeAllForUser(userID string, before time.Time) error {
+	tx := rr.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked_before", "updated_at"}),
+	}).Create(&model.UserRevocation{
+		UserID:        userID,
+		RevokedBefore: before.Truncate(time.Second),
+	})
+	return tx.Error
+}
+
+func (rr *RevocationRepository) IsRevoked(jti string, userID string, issuedAt time.Time) (bool, error) {
+	userRevocation := model.UserRevocation{}
+	err := rr.db.Where("user_id = ?", userID).Take(&userRevocation).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	if err == nil && issuedAt.Before(userRevocation.RevokedBefore) {
+		return true, nil
+	}
+
+	var count int64
+	err = rr.db.Model(&model.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
+
+// InMemoryRevocationRepository keeps revocations in process memory. It is meant
+// for single instance deployments and local development, entries are lost on restart.
+type InMemoryRevocationRepository struct {
+	mu              sync.RWMutex
+	tokens          map[string]time.Time
+	userRevocations map[string]time.Time
+}
+
+func NewInMemoryRevocationRepository() *InMemoryRevocationRepository {
+	return &InMemoryRevocationRepository{
+		tokens:          map[string]time.Time{},
+		userRevocations: map[string]time.Time{},
+	}
+}
+
+func (mr *InMemoryRevocationRepository) Revoke(jti string, userID string, expiresAt time.Time) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	now := time.Now()
+	for revokedJTI, revokedExpiresAt := range mr.tokens {
+		if revokedExpiresAt.Before(now) {
+			delete(mr.tokens, revokedJTI)
+		}
+	}
+
+	mr.tokens[jti] = expiresAt
+	return nil
+}
+
+func (mr *InMemoryRevocationRepository) RevokeAllForUser(userID string, before time.Time) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	mr.userRevocations[userID] = before.Truncate(time.Second)
+	return nil
+}
+
+func (mr *InMemoryRevocationRepository) IsRevoked(jti string, userID string, issuedAt time.Time) (bool, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	if before, ok := mr.userRevocations[userID]; ok && issuedAt.Before(before) {
+		return true, nil
+	}
+
+	_, ok := mr.tokens[jti]
+	return ok, nil
+}
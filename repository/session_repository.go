@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"errors"
+	"finalProject/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// lastSeenPrecision limits how often an active session writes its last-seen time.
+const lastSeenPrecision = time.Minute
+
+type ISessionRepository interface {
+	Add(newSession model.Session) error
+	GetByID(sessionID string) (model.Session, error)
+	FindActiveByUser(userID string, since time.Time) ([]model.Session, error)
+	Touch(sessionID string, ip string) error
+	Revoke(sessionID string, userID string) error
+	RevokeAllForUser(userID string) error
+}
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{
+		db: db,
+	}
+}
+
+func (sr *SessionRepository) Add(newSession model.Session) error {
+	tx := sr.db.Create(&newSession)
+	return tx.Error
+}
+
+func (sr *SessionRepository) GetByID(sessionID string) (model.Session, error) {
+	session := model.Session{}
+
+	err := sr.db.Where("id = ?", sessionID).Take(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Session{}, model.ErrorNotFound
+	}
+
+	return session, err
+}
+
+// FindActiveByUser lists the sessions that were not revoked and were seen
+// after since, most recently seen first.
+func (sr *SessionRepository) FindActiveByUser(userID string, since time.Time) ([]model.Session, error) {
+	sessions := []model.Session{}
+
+	tx := sr.db.Where("user_id = ? AND revoked_at IS NULL AND last_seen_at > ?", userID, since).
+		Order("last_seen_at desc").
+		Find(&sessions)
+	return sessions, tx.Error
+}
+
+// Touch records activity on the session, at most once per lastSeenPrecision.
+func (sr *SessionRepository) Touch(sessionID string, ip string) error {
+	now := time.Now()
+	tx := sr.db.Model(&model.Session{}).
+		Where("id = ? AND revoked_at IS NULL AND last_seen_at < ?", sessionID, now.Add(-lastSeenPrecision)).
+		Updates(map[string]interface{}{
+			"last_seen_at": now,
+			"ip":           ip,
+		})
+	return tx.Error
+}
+
+func (sr *SessionRepository) Revoke(sessionID string, userID string) error {
+	tx := sr.db.Model(&model.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", time.Now())
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return model.ErrorNotFound
+	}
+	return nil
+}
+
+func (sr *SessionRepository) RevokeAllForUser(userID string) error {
+	tx := sr.db.Model(&model.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	return tx.Error
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"errors"
 	"finalProject/model"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -10,10 +11,15 @@ import (
 
 type ISocialMediaRepository interface {
 	Add(newSocial model.SocialMedia) error
-	Get() ([]model.SocialMedia, error)
+	Get(viewerID string) ([]model.SocialMedia, error)
+	FindByUser(userID string) ([]model.SocialMedia, error)
 	GetOne(SocialID string) (model.SocialMedia, error)
 	Update(updateSocialMedia model.SocialMedia, socialId string) (model.SocialMedia, error)
-	Delete(socialID string) error
+	Delete(socialID string, deletedBy string) error
+	GetDeleted(socialID string, since time.Time) (model.SocialMedia, error)
+	FindDeleted(userID string, since time.Time) ([]model.SocialMedia, error)
+	Restore(socialID string) error
+	PurgeDeleted(before time.Time) error
 }
 
 type SocialMediaRepository struct {
@@ -31,10 +37,18 @@ func (sr *SocialMediaRepository) Add(newSocial model.SocialMedia) error {
 	return tx.Error
 }
 
-func (sr *SocialMediaRepository) Get() ([]model.SocialMedia, error) {
+// Get lists the social media of every account the viewer may see.
+func (sr *SocialMediaRepository) Get(viewerID string) ([]model.SocialMedia, error) {
 	socMed := []model.SocialMedia{}
 
-	tx := sr.db.Find(&socMed)
+	tx := sr.db.Where("user_id NOT IN (?)", privateUsersHiddenFrom(sr.db, viewerID)).Find(&socMed)
+	return socMed, tx.Error
+}
+
+func (sr *SocialMediaRepository) FindByUser(userID string) ([]model.SocialMedia, error) {
+	socMed := []model.SocialMedia{}
+
+	tx := sr.db.Where("user_id = ?", userID).Order("created_at").Find(&socMed)
 	return socMed, tx.Error
 }
 
@@ -61,12 +75,53 @@ func (sr *SocialMediaRepository) Update(updateSocialMedia model.SocialMedia, soc
 	return updateSocialMedia, err.Error
 }
 
-func (sr *SocialMediaRepository) Delete(socialID string) error {
-	delSocial := model.SocialMedia{}
+// Delete moves the social media to the trash.
+func (sr *SocialMediaRepository) Delete(socialID string, deletedBy string) error {
+	tx := sr.db.Model(&model.SocialMedia{}).Where("social_id = ?", socialID).UpdateColumns(map[string]interface{}{
+		"deleted_at": time.Now(),
+		"deleted_by": deletedBy,
+	})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
 
-	tx := sr.db.Delete(&delSocial, "social_id = ?", socialID)
+// GetDeleted returns a social media in the trash that was deleted after since.
+func (sr *SocialMediaRepository) GetDeleted(socialID string, since time.Time) (model.SocialMedia, error) {
+	socialMedia := model.SocialMedia{}
+
+	err := sr.db.Unscoped().Where("social_id = ? AND deleted_at > ?", socialID, since).Take(&socialMedia).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.SocialMedia{}, model.ErrorNotFound
+	}
+	return socialMedia, err
+}
+
+// FindDeleted lists the social media the user deleted after since, latest
+// deletion first. Social media removed by a moderator are left out.
+func (sr *SocialMediaRepository) FindDeleted(userID string, since time.Time) ([]model.SocialMedia, error) {
+	socMed := []model.SocialMedia{}
+
+	tx := sr.db.Unscoped().Where("user_id = ? AND deleted_by = ? AND deleted_at > ?", userID, userID, since).Order("deleted_at desc").Find(&socMed)
+	return socMed, tx.Error
+}
+
+func (sr *SocialMediaRepository) Restore(socialID string) error {
+	tx := sr.db.Unscoped().Model(&model.SocialMedia{}).
+		Where("social_id = ? AND deleted_at IS NOT NULL", socialID).
+		UpdateColumns(map[string]interface{}{"deleted_at": nil, "deleted_by": ""})
 	if tx.Error != nil {
 		return tx.Error
 	}
+	if tx.RowsAffected == 0 {
+		return model.ErrorNotFound
+	}
 	return nil
 }
+
+// PurgeDeleted removes for good the social media deleted before before.
+func (sr *SocialMediaRepository) PurgeDeleted(before time.Time) error {
+	tx := sr.db.Unscoped().Where("deleted_at < ?", before).Delete(&model.SocialMedia{})
+	return tx.Error
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"errors"
+	"finalProject/model"
+
+	"gorm.io/gorm"
+)
+
+type IUserIdentityRepository interface {
+	Get(issuer string, subject string) (model.UserIdentity, error)
+	Add(identity model.UserIdentity) error
+	AddWithUser(newUser model.User, identity model.UserIdentity) (model.User, error)
+}
+
+type UserIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewUserIdentityRepository(db *gorm.DB) *UserIdentityRepository {
+	return &UserIdentityRepository{
+		db: db,
+	}
+}
+
+func (ir *UserIdentityRepository) Get(issuer string, subject string) (model.UserIdentity, error) {
+	identity := model.UserIdentity{}
+
+	err := ir.db.Where("issuer = ? AND subject = ?", issuer, subject).Take(&identity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.UserIdentity{}, model.ErrorNotFound
+	}
+
+	return identity, err
+}
+
+func (ir *UserIdentityRepository) Add(identity model.UserIdentity) error {
+	tx := ir.db.Create(&identity)
+	return tx.Error
+}
+
+// AddWithUser creates the user of a first provider login together with the link.
+func (ir *UserIdentityRepository) AddWithUser(newUser model.User, identity model.UserIdentity) (model.User, error) {
+	err := ir.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&newUser).Error
+		if err != nil {
+			return translateUniqueViolation(err)
+		}
+
+		return tx.Create(&identity).Error
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return newUser, nil
+}
@@ -1,15 +1,37 @@
 package repository
 
 import (
+	"errors"
 	"finalProject/model"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type IUserRepository interface {
 	Add(newUser model.User) (model.User, error)
 	GetByEmail(email string) (model.User, error)
 	GetByUsername(username string) (model.User, error)
+	GetByID(userID string) (model.User, error)
+	Update(updateUser model.User, userID string) (model.User, error)
+	UpdatePassword(userID string, hashPassword string) error
+	RehashPassword(userID string, oldHash string, newHash string) error
+	UpdateRole(userID string, role string) error
+	SetAge(userID string, age int, at time.Time) error
+	SetEmailVerified(userID string, verifiedAt *time.Time) error
+	SetPrivate(userID string, isPrivate bool) error
+	SetTOTPSecret(userID string, secret string) error
+	EnableTOTP(userID string, enabledAt time.Time, step int64) error
+	DisableTOTP(userID string) error
+	UseTOTPStep(userID string, step int64) (bool, error)
+	Delete(userID string) error
+	GetDeleted(userID string, since time.Time) (model.User, error)
+	Restore(userID string, deletedAt time.Time) error
+	Purge(userID string) error
+	PurgeDeleted(before time.Time) error
 }
 
 type UserRepository struct {
@@ -25,7 +47,7 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 func (ur *UserRepository) Add(newUser model.User) (model.User, error) {
 	tx := ur.db.Create(&newUser)
 	if tx.Error != nil {
-		return model.User{}, tx.Error
+		return model.User{}, translateUniqueViolation(tx.Error)
 	}
 	return newUser, nil
 }
@@ -41,3 +63,400 @@ func (ur *UserRepository) GetByUsername(username string) (model.User, error) {
 	tx := ur.db.First(&user, "username = ?", username)
 	return user, tx.Error
 }
+
+func (ur *UserRepository) GetByID(userID string) (model.User, error) {
+	var user model.User
+	err := ur.db.Where("id = ?", userID).Take(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.User{}, model.ErrorNotFound
+	}
+	return user, err
+}
+
+func (ur *UserRepository) Update(updateUser model.User, userID string) (model.User, error) {
+	tx := ur.db.Clauses(clause.Returning{}).Where("id = ?", userID).Updates(&updateUser)
+	if tx.Error != nil {
+		return model.User{}, translateUniqueViolation(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return model.User{}, model.ErrorNotFound
+	}
+	return updateUser, nil
+}
+
+func (ur *UserRepository) UpdatePassword(userID string, hashPassword string) error {
+	tx := ur.db.Model(&model.User{}).Where("id = ?", userID).Update("password", hashPassword)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return model.ErrorNotFound
+	}
+	return nil
+}
+
+// RehashPassword swaps the stored hash for a new hash of the same password.
+// It does nothing when the password changed in the meantime.
+func (ur *UserRepository) RehashPassword(userID string, oldHash string, newHash string) error {
+	tx := ur.db.Model(&model.User{}).Where("id = ? AND password = ?", userID, oldHash).Update("password", newHash)
+	return tx.Error
+}
+
+func (ur *UserRepository) UpdateRole(userID string, role string) error {
+	tx := ur.db.Model(&model.User{}).Where("id = ?", userID).Update("role", role)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return model.ErrorNotFound
+	}
+	return nil
+}
+
+// SetAge records the age as of at and locks it against changes by the user.
+func (ur *UserRepository) SetAge(userID string, age int, at time.Time) error {
+	tx := ur.db.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"age":             age,
+		"age_recorded_at": at,
+		"age_locked_at":   at,
+	})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return model.ErrorNotFound
+	}
+	return nil
+}
+
+// SetEmailVerified marks the email as verified, or unverified when verifiedAt is nil.
+func (ur *UserRepository) SetEmailVerified(userID string, verifiedAt *time.Time) error {
+	tx := ur.db.Model(&model.User{}).Where("id = ?", userID).Update("email_verified_at", verifiedAt)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return model.ErrorNotFound
+	}
+	return nil
+}
+
+func (ur *UserRepository) SetPrivate(userID string, isPrivate bool) error {
+	tx := ur.db.Model(&model.User{}).Where("id = ?", userID).Update("is_private", isPrivate)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return model.ErrorNotFound
+	}
+	return nil
+}
+
+// SetTOTPSecret stores a pending secret, it is ignored once two-factor is enabled.
+func (ur *UserRepository) SetTOTPSecret(userID string, secret string) error {
+	tx := ur.db.Model(&model.User{}).
+		Where("id = ? AND totp_enabled_at IS NULL", userID).
+		Update("totp_secret", secret)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return model.ErrorTwoFactorAlreadyEnabled
+	}
+	return nil
+}
+
+func (ur *UserRepository) EnableTOTP(userID string, enabledAt time.Time, step int64) error {
+	tx := ur.db.Model(&model.User{}).
+		Where("id = ? AND totp_enabled_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"totp_enabled_at": enabledAt,
+			"totp_last_step":  step,
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return model.ErrorTwoFactorAlreadyEnabled
+	}
+	return nil
+}
+
+func (ur *UserRepository) DisableTOTP(userID string) error {
+	tx := ur.db.Model(&model.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"totp_secret":     "",
+			"totp_enabled_at": nil,
+			"totp_last_step":  0,
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return model.ErrorNotFound
+	}
+	return nil
+}
+
+// UseTOTPStep records the time step of an accepted code. It reports false when
+// that step or a later one was already used, which stops a code being replayed.
+func (ur *UserRepository) UseTOTPStep(userID string, step int64) (bool, error) {
+	tx := ur.db.Model(&model.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	return tx.RowsAffected == 1, nil
+}
+
+// Delete moves the user to the trash together with their photos, the
+// comments on those photos, their own comments and social media, all with the
+// same deletion time so Restore brings back exactly those. Follows, blocks and
+// mutes are kept for a restore, pending follow requests and the sessions and
+// tokens that keep the account signed in are removed.
+func (ur *UserRepository) Delete(userID string) error {
+	now := time.Now()
+	deleted := map[string]interface{}{"deleted_at": now, "deleted_by": userID}
+
+	return ur.db.Transaction(func(tx *gorm.DB) error {
+		photoIDs := tx.Model(&model.Photo{}).Select("photo_id").Where("user_id = ?", userID)
+
+		err := tx.Model(&model.Comment{}).Where("photo_id IN (?) OR user_id = ?", photoIDs, userID).UpdateColumns(deleted).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.Photo{}).Where("user_id = ?", userID).UpdateColumns(deleted).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.SocialMedia{}).Where("user_id = ?", userID).UpdateColumns(deleted).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("requester_id = ? OR target_id = ?", userID, userID).Delete(&model.FollowRequest{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("user_id = ?", userID).Delete(&model.RefreshToken{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("user_id = ?", userID).Delete(&model.Session{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("user_id = ?", userID).Delete(&model.PasswordReset{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("user_id = ?", userID).Delete(&model.PersonalAccessToken{}).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.User{}).Where("id = ?", userID).UpdateColumn("deleted_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrorNotFound
+		}
+		return nil
+	})
+}
+
+// GetDeleted returns a user in the trash that was deleted after since.
+func (ur *UserRepository) GetDeleted(userID string, since time.Time) (model.User, error) {
+	var user model.User
+	err := ur.db.Unscoped().Where("id = ? AND deleted_at > ?", userID, since).Take(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.User{}, model.ErrorNotFound
+	}
+	return user, err
+}
+
+// Restore takes the user out of the trash with the content deleted along with
+// the account. Content the user deleted before stays in the trash.
+func (ur *UserRepository) Restore(userID string, deletedAt time.Time) error {
+	restored := map[string]interface{}{"deleted_at": nil, "deleted_by": ""}
+
+	return ur.db.Transaction(func(tx *gorm.DB) error {
+		photoIDs := tx.Unscoped().Model(&model.Photo{}).Select("photo_id").Where("user_id = ?", userID)
+
+		err := tx.Unscoped().Model(&model.Comment{}).
+			Where("(photo_id IN (?) OR user_id = ?) AND deleted_at = ?", photoIDs, userID, deletedAt).
+			UpdateColumns(restored).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Model(&model.Photo{}).
+			Where("user_id = ? AND deleted_at = ?", userID, deletedAt).
+			UpdateColumns(restored).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Model(&model.SocialMedia{}).
+			Where("user_id = ? AND deleted_at = ?", userID, deletedAt).
+			UpdateColumns(restored).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Model(&model.User{}).
+			Where("id = ? AND deleted_at = ?", userID, deletedAt).
+			UpdateColumn("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrorNotFound
+		}
+		return nil
+	})
+}
+
+// Purge removes the user for good together with their photos, the comments
+// on those photos, their own comments, social media, auth tokens and data
+// exports in one transaction, whether they are in the trash or not.
+func (ur *UserRepository) Purge(userID string) error {
+	return ur.db.Transaction(func(tx *gorm.DB) error {
+		photoIDs := tx.Unscoped().Model(&model.Photo{}).Select("photo_id").Where("user_id = ?", userID)
+
+		err := tx.Unscoped().Where("photo_id IN (?) OR user_id = ?", photoIDs, userID).Delete(&model.Comment{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Where("user_id = ?", userID).Delete(&model.Photo{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Where("user_id = ?", userID).Delete(&model.SocialMedia{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("follower_id = ? OR followee_id = ?", userID, userID).Delete(&model.Follow{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("user_id = ? OR author_id = ?", userID, userID).Delete(&model.FeedItem{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("user_id = ?", userID).Delete(&model.FeedPullAuthor{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("requester_id = ? OR target_id = ?", userID, userID).Delete(&model.FollowRequest{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("blocker_id = ? OR blocked_id = ?", userID, userID).Delete(&model.Block{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("muter_id = ? OR muted_id = ?", userID, userID).Delete(&model.Mute{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("user_id = ?", userID).Delete(&model.RefreshToken{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("user_id = ?", userID).Delete(&model.Session{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("user_id = ?", userID).Delete(&model.PasswordReset{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("user_id = ?", userID).Delete(&model.PersonalAccessToken{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("user_id = ?", userID).Delete(&model.UserIdentity{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("user_id = ?", userID).Delete(&model.DataExport{}).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where("id = ?", userID).Delete(&model.User{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrorNotFound
+		}
+		return nil
+	})
+}
+
+// PurgeDeleted removes for good the users deleted before before, one
+// transaction per user.
+func (ur *UserRepository) PurgeDeleted(before time.Time) error {
+	userIDs := []string{}
+
+	err := ur.db.Unscoped().Model(&model.User{}).Where("deleted_at < ?", before).Pluck("id", &userIDs).Error
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		err = ur.Purge(userID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// translateUniqueViolation turns a unique constraint error on users, which can
+// still happen when two registrations race, into the matching field error.
+func translateUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return err
+	}
+
+	switch {
+	case strings.Contains(pgErr.ConstraintName, "email"):
+		return model.ErrorEmailAlreadyExists
+	case strings.Contains(pgErr.ConstraintName, "username"):
+		return model.ErrorUsernameAlreadyExists
+	}
+	return err
+}
@@ -2,9 +2,13 @@ package router
 
 import (
 	"finalProject/controller"
+	"finalProject/mailer"
 	"finalProject/middleware"
+	"finalProject/model"
+	"finalProject/oidc"
 	"finalProject/repository"
 	"finalProject/service"
+	"os"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -15,6 +19,9 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// REVOCATION_STORE selects where revoked tokens are kept: "postgres" (default) or "memory".
+var REVOCATION_STORE = os.Getenv("REVOCATION_STORE")
+
 // @title							Mygram API
 // @version							1.0
 // @description						Final Project for Scalable Web Service with Golang DTS-FGA
@@ -30,28 +37,156 @@ func StartApp(router *gin.Engine, db *gorm.DB) {
 	userRepository := repository.NewUserRepository(db)
 	photoRepository := repository.NewPhotoRepository(db)
 	SocialMediaRepository := repository.NewSocialMediaRepository(db)
+	refreshTokenRepository := repository.NewRefreshTokenRepository(db)
+	passwordResetRepository := repository.NewPasswordResetRepository(db)
+	loginAttemptRepository := repository.NewInMemoryLoginAttemptRepository()
+	loginLockoutRepository := repository.NewLoginLockoutRepository(db)
+	recoveryCodeRepository := repository.NewRecoveryCodeRepository(db)
+	personalAccessTokenRepository := repository.NewPersonalAccessTokenRepository(db)
+	userIdentityRepository := repository.NewUserIdentityRepository(db)
+	oidcStateRepository := repository.NewOIDCStateRepository(db)
+	sessionRepository := repository.NewSessionRepository(db)
+	followRepository := repository.NewFollowRepository(db)
+	blockRepository := repository.NewBlockRepository(db)
+	muteRepository := repository.NewMuteRepository(db)
+	feedRepository := repository.NewFeedRepository(db)
+	followRequestRepository := repository.NewFollowRequestRepository(db)
+	dataExportRepository := repository.NewDataExportRepository(db)
+
+	mail, err := mailer.NewMailer()
+	if err != nil {
+		panic(err)
+	}
+
+	oidcProvider, err := oidc.NewProvider()
+	if err != nil {
+		panic(err)
+	}
+
+	var revocationRepository repository.IRevocationRepository = repository.NewRevocationRepository(db)
+	if REVOCATION_STORE == "memory" {
+		revocationRepository = repository.NewInMemoryRevocationRepository()
+	}
 
-	photoService := service.NewPhotoService(photoRepository, commentRepository)
+	auth := middleware.NewAuthenticator(revocationRepository, personalAccessTokenRepository, sessionRepository)
+
+	photoService := service.NewPhotoService(photoRepository, commentRepository, blockRepository, muteRepository, followRepository, feedRepository, userRepository)
 	photoController := controller.NewPhotoController(*photoService)
 
-	commentService := service.NewCommentService(commentRepository, photoRepository)
+	commentService := service.NewCommentService(commentRepository, photoRepository, blockRepository, muteRepository, followRepository, userRepository)
 	commentController := controller.NewCommentController(*commentService)
 
-	userService := service.NewUserService(*userRepository)
+	loginGuard := service.NewLoginGuard(loginAttemptRepository, loginLockoutRepository)
+
+	userService := service.NewUserService(*userRepository, refreshTokenRepository, revocationRepository, recoveryCodeRepository, sessionRepository, personalAccessTokenRepository, mail, loginGuard)
 	userController := controller.NewUserController(*userService)
 
-	SocialMediaService := service.NewSocialMediaService(SocialMediaRepository)
+	oidcService := service.NewOIDCService(oidcProvider, userRepository, userIdentityRepository, oidcStateRepository, userService)
+	oidcController := controller.NewOIDCController(*oidcService)
+
+	passwordService := service.NewPasswordService(userRepository, passwordResetRepository, refreshTokenRepository, revocationRepository, sessionRepository, personalAccessTokenRepository, mail, loginGuard)
+	passwordController := controller.NewPasswordController(*passwordService)
+
+	twoFactorService := service.NewTwoFactorService(userRepository, recoveryCodeRepository)
+	twoFactorController := controller.NewTwoFactorController(*twoFactorService)
+
+	personalAccessTokenService := service.NewPersonalAccessTokenService(personalAccessTokenRepository)
+	personalAccessTokenController := controller.NewPersonalAccessTokenController(*personalAccessTokenService)
+
+	sessionService := service.NewSessionService(sessionRepository, refreshTokenRepository)
+	sessionController := controller.NewSessionController(*sessionService)
+
+	adminService := service.NewAdminService(userRepository, refreshTokenRepository, revocationRepository, sessionRepository, personalAccessTokenRepository, loginGuard)
+	adminController := controller.NewAdminController(*adminService)
+
+	profileService := service.NewProfileService(userRepository, photoRepository, commentRepository, SocialMediaRepository, followRepository, blockRepository)
+	profileController := controller.NewProfileController(*profileService)
+
+	followService := service.NewFollowService(userRepository, followRepository, followRequestRepository, blockRepository, feedRepository)
+	followController := controller.NewFollowController(*followService)
+
+	blockService := service.NewBlockService(userRepository, blockRepository, muteRepository)
+	blockController := controller.NewBlockController(*blockService)
+
+	feedService := service.NewFeedService(feedRepository, photoRepository, blockRepository, muteRepository, userRepository)
+	feedController := controller.NewFeedController(*feedService)
+
+	dataExportService := service.NewDataExportService(dataExportRepository, userRepository, photoRepository, commentRepository, SocialMediaRepository)
+	dataExportController := controller.NewDataExportController(*dataExportService)
+	go dataExportService.Run()
+
+	trashService := service.NewTrashService(userRepository, photoRepository, commentRepository, SocialMediaRepository)
+	trashController := controller.NewTrashController(*trashService)
+	go trashService.Run()
+
+	SocialMediaService := service.NewSocialMediaService(SocialMediaRepository, followRepository)
 	SocialMediaController := controller.NewSocialMediaController(*SocialMediaService)
 
 	router.GET("", controller.HomeController)
+	router.GET("/.well-known/jwks.json", controller.JWKSController)
 	base := router.Group("/mygram")
 	{
 		user := base.Group("/user")
 		{
 			user.POST("/register", userController.Register)
 			user.POST("/login", userController.Login)
+			user.POST("/login/2fa", userController.LoginTwoFactor)
+			user.POST("/refresh", userController.Refresh)
+			user.GET("/oidc/login", oidcController.OIDCLogin)
+			user.GET("/oidc/callback", oidcController.OIDCCallback)
+			user.POST("/logout", auth.AuthMiddleware, userController.Logout)
+			user.POST("/logout/all", auth.AuthMiddleware, userController.LogoutAll)
+			user.GET("/me", auth.AuthMiddleware, userController.GetMe)
+			user.PUT("/me", auth.AuthMiddleware, userController.UpdateMe)
+			user.DELETE("/me", auth.AuthMiddleware, userController.DeleteMe)
+			user.PUT("/me/password", auth.AuthMiddleware, passwordController.ChangePassword)
+			user.PUT("/me/privacy", auth.AuthMiddleware, followController.SetPrivacy)
+			user.POST("/me/2fa/setup", auth.AuthMiddleware, twoFactorController.Setup)
+			user.POST("/me/2fa/enable", auth.AuthMiddleware, twoFactorController.Enable)
+			user.POST("/me/2fa/disable", auth.AuthMiddleware, twoFactorController.Disable)
+			user.POST("/tokens", auth.AuthMiddleware, personalAccessTokenController.CreateToken)
+			user.GET("/tokens", auth.AuthMiddleware, personalAccessTokenController.GetAllToken)
+			user.DELETE("/tokens/:token_id", auth.AuthMiddleware, personalAccessTokenController.RevokeToken)
+			user.GET("/sessions", auth.AuthMiddleware, sessionController.GetAllSession)
+			user.DELETE("/sessions/:session_id", auth.AuthMiddleware, sessionController.RevokeSession)
+			user.GET("/blocks", auth.AuthMiddleware, blockController.GetBlocked)
+			user.GET("/mutes", auth.AuthMiddleware, blockController.GetMuted)
+			user.GET("/follow-requests", auth.AuthMiddleware, followController.GetFollowRequests)
+			user.POST("/follow-requests/:username/accept", auth.AuthMiddleware, followController.AcceptFollowRequest)
+			user.POST("/follow-requests/:username/reject", auth.AuthMiddleware, followController.RejectFollowRequest)
+			user.POST("/exports", auth.AuthMiddleware, dataExportController.RequestExport)
+			user.GET("/exports/:export_id", auth.AuthMiddleware, dataExportController.GetExport)
+			user.GET("/exports/:export_id/download", auth.AuthMiddleware, dataExportController.DownloadExport)
+			user.GET("/trash", auth.AuthMiddleware, trashController.GetTrash)
+			user.POST("/trash/photos/:photo_id/restore", auth.AuthMiddleware, trashController.RestorePhoto)
+			user.POST("/trash/comments/:comment_id/restore", auth.AuthMiddleware, trashController.RestoreComment)
+			user.POST("/trash/social_media/:social_id/restore", auth.AuthMiddleware, trashController.RestoreSocialMedia)
+			user.POST("/password/forgot", passwordController.ForgotPassword)
+			user.POST("/password/reset", passwordController.ResetPassword)
+			user.GET("/verify", userController.VerifyEmail)
+			user.POST("/verify/resend", userController.ResendVerification)
+		}
+		users := base.Group("/users", auth.AuthMiddleware)
+		{
+			users.GET("/:username", profileController.GetProfile)
+			users.POST("/:username/follow", followController.Follow)
+			users.DELETE("/:username/follow", followController.Unfollow)
+			users.GET("/:username/followers", followController.GetFollowers)
+			users.GET("/:username/following", followController.GetFollowing)
+			users.POST("/:username/block", blockController.Block)
+			users.DELETE("/:username/block", blockController.Unblock)
+			users.POST("/:username/mute", blockController.Mute)
+			users.DELETE("/:username/mute", blockController.Unmute)
+		}
+		adminAuth := base.Group("/admin", auth.AuthMiddleware, middleware.RequireRole(model.RoleAdmin))
+		{
+			adminAuth.PUT("/users/:user_id/role", adminController.SetRole)
+			adminAuth.PUT("/users/:user_id/age", adminController.SetAge)
+			adminAuth.POST("/users/:user_id/unlock", adminController.UnlockLogin)
+			adminAuth.POST("/users/:user_id/restore", trashController.RestoreUser)
 		}
-		withAuth := base.Group("/photos", middleware.AuthMiddleware)
+		base.GET("/feed", auth.ScopedAuthMiddleware("photos"), feedController.GetFeed)
+		withAuth := base.Group("/photos", auth.ScopedAuthMiddleware("photos"))
 		{
 			withAuth.POST("/create", photoController.CreatePhoto)
 			withAuth.GET("/get/all", photoController.GetAllPhoto)
@@ -59,7 +194,7 @@ func StartApp(router *gin.Engine, db *gorm.DB) {
 			withAuth.PUT("/update/:photo_id", photoController.PhotoUpdate)
 			withAuth.DELETE("/delete/:photo_id", photoController.DeletePhoto)
 		}
-		commentAuth := base.Group("/comments", middleware.AuthMiddleware)
+		commentAuth := base.Group("/comments", auth.ScopedAuthMiddleware("comments"))
 		{
 			commentAuth.POST("/:photo_id", commentController.CreateComment)
 			commentAuth.GET("/get/all", commentController.GetAllComment)
@@ -67,7 +202,7 @@ func StartApp(router *gin.Engine, db *gorm.DB) {
 			commentAuth.PUT("/update/:comment_id", commentController.UpdateComment)
 			commentAuth.DELETE("/delete/:comment_id", commentController.DeleteComment)
 		}
-		socialAuth := base.Group("/social_media", middleware.AuthMiddleware)
+		socialAuth := base.Group("/social_media", auth.ScopedAuthMiddleware("social_media"))
 		{
 			socialAuth.POST("/", SocialMediaController.CreateSocialMedia)
 			socialAuth.GET("/get/all", SocialMediaController.GetAllSocialMedia)
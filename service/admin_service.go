@@ -0,0 +1,93 @@
+package service
+
+import (
+	"finalProject/model"
+	"finalProject/repository"
+	"time"
+)
+
+type IAdminService interface {
+	SetRole(request model.UserSetRoleRequest, userID string, adminID string) (model.UserResponse, error)
+	SetAge(request model.UserSetAgeRequest, userID string) (model.UserResponse, error)
+	UnlockLogin(userID string) error
+}
+
+type AdminService struct {
+	UserRepository         repository.IUserRepository
+	RefreshTokenRepository repository.IRefreshTokenRepository
+	RevocationRepository   repository.IRevocationRepository
+	SessionRepository      repository.ISessionRepository
+	TokenRepository        repository.IPersonalAccessTokenRepository
+	LoginGuard             *LoginGuard
+}
+
+func NewAdminService(userRepository repository.IUserRepository, refreshTokenRepository repository.IRefreshTokenRepository, revocationRepository repository.IRevocationRepository, sessionRepository repository.ISessionRepository, tokenRepository repository.IPersonalAccessTokenRepository, loginGuard *LoginGuard) *AdminService {
+	return &AdminService{
+		UserRepository:         userRepository,
+		RefreshTokenRepository: refreshTokenRepository,
+		RevocationRepository:   revocationRepository,
+		SessionRepository:      sessionRepository,
+		TokenRepository:        tokenRepository,
+		LoginGuard:             loginGuard,
+	}
+}
+
+// SetRole changes the role of a user. The role is carried in the access
+// token, so the user's tokens are revoked and the new role applies from the
+// next login. Admins cannot change their own role, which keeps at least one admin.
+func (as *AdminService) SetRole(request model.UserSetRoleRequest, userID string, adminID string) (model.UserResponse, error) {
+	if userID == adminID {
+		return model.UserResponse{}, model.ErrorCannotChangeOwnRole
+	}
+
+	err := as.UserRepository.UpdateRole(userID, request.Role)
+	if err != nil {
+		return model.UserResponse{}, err
+	}
+
+	err = revokeAllTokens(as.RevocationRepository, as.RefreshTokenRepository, as.SessionRepository, as.TokenRepository, userID)
+	if err != nil {
+		return model.UserResponse{}, err
+	}
+
+	user, err := as.UserRepository.GetByID(userID)
+	if err != nil {
+		return model.UserResponse{}, err
+	}
+
+	return toUserResponse(user), nil
+}
+
+// SetAge corrects the age of a user, for example one who claimed to be older,
+// and keeps the user from changing it again. A restricted age makes the
+// account private.
+func (as *AdminService) SetAge(request model.UserSetAgeRequest, userID string) (model.UserResponse, error) {
+	err := as.UserRepository.SetAge(userID, request.Age, time.Now())
+	if err != nil {
+		return model.UserResponse{}, err
+	}
+
+	if isRestrictedAge(request.Age) {
+		err = as.UserRepository.SetPrivate(userID, true)
+		if err != nil {
+			return model.UserResponse{}, err
+		}
+	}
+
+	user, err := as.UserRepository.GetByID(userID)
+	if err != nil {
+		return model.UserResponse{}, err
+	}
+
+	return toUserResponse(user), nil
+}
+
+// UnlockLogin lifts a login lockout on the account before it runs out.
+func (as *AdminService) UnlockLogin(userID string) error {
+	_, err := as.UserRepository.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	return as.LoginGuard.Unlock(accountKey(userID))
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"finalProject/helper"
+	"finalProject/model"
+	"finalProject/repository"
+	"time"
+)
+
+var (
+	// MATURE_CONTENT_MIN_AGE is the age from which mature photos are shown.
+	MATURE_CONTENT_MIN_AGE = helper.GetEnvInt("MATURE_CONTENT_MIN_AGE", 18)
+	// RESTRICTED_ACCOUNT_AGE is the age below which new accounts start with
+	// restricted defaults.
+	RESTRICTED_ACCOUNT_AGE = helper.GetEnvInt("RESTRICTED_ACCOUNT_AGE", 13)
+)
+
+// canViewMature reports whether the viewer is old enough for mature photos.
+// An unknown age, 0 for accounts created through an external login, counts
+// as too young.
+func canViewMature(userRepository repository.IUserRepository, viewerID string) (bool, error) {
+	viewer, err := userRepository.GetByID(viewerID)
+	if err != nil {
+		return false, err
+	}
+	return currentAge(viewer) >= MATURE_CONTENT_MIN_AGE, nil
+}
+
+// currentAge counts the full years since the age was recorded on top of it,
+// so accounts grow older without editing their profile. An unknown age stays 0.
+func currentAge(user model.User) int {
+	if user.Age == 0 {
+		return 0
+	}
+
+	recordedAt := user.CreatedAt
+	if user.AgeRecordedAt != nil {
+		recordedAt = *user.AgeRecordedAt
+	}
+
+	now := time.Now()
+	years := now.Year() - recordedAt.Year()
+	if recordedAt.AddDate(years, 0, 0).After(now) {
+		years--
+	}
+	if years < 0 {
+		years = 0
+	}
+	return user.Age + years
+}
+
+// checkContentRating returns ErrorNotFound for a mature photo when the viewer
+// is too young, so the photo looks missing to them.
+func checkContentRating(userRepository repository.IUserRepository, photo model.Photo, viewerID string) error {
+	if photo.ContentRating != model.ContentRatingMature {
+		return nil
+	}
+
+	mature, err := canViewMature(userRepository, viewerID)
+	if err != nil {
+		return err
+	}
+	if !mature {
+		return model.ErrorNotFound
+	}
+	return nil
+}
+
+// isRestrictedAge reports whether an account of this age gets the restricted
+// defaults: the account starts private. An unknown age counts as restricted.
+func isRestrictedAge(age int) bool {
+	return age < RESTRICTED_ACCOUNT_AGE
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"errors"
+	"finalProject/model"
+	"finalProject/repository"
+
+	"gorm.io/gorm"
+)
+
+type IBlockService interface {
+	Block(username string, userID string) error
+	Unblock(username string, userID string) error
+	Mute(username string, userID string) error
+	Unmute(username string, userID string) error
+	GetBlocked(userID string, query model.PageQuery) (model.UserRelationListResponse, error)
+	GetMuted(userID string, query model.PageQuery) (model.UserRelationListResponse, error)
+}
+
+type BlockService struct {
+	UserRepository  repository.IUserRepository
+	BlockRepository repository.IBlockRepository
+	MuteRepository  repository.IMuteRepository
+}
+
+func NewBlockService(userRepository repository.IUserRepository, blockRepository repository.IBlockRepository, muteRepository repository.IMuteRepository) *BlockService {
+	return &BlockService{
+		UserRepository:  userRepository,
+		BlockRepository: blockRepository,
+		MuteRepository:  muteRepository,
+	}
+}
+
+// Block is idempotent and ends the follows between the two users.
+func (bs *BlockService) Block(username string, userID string) error {
+	user, err := bs.getUser(username)
+	if err != nil {
+		return err
+	}
+
+	if user.ID == userID {
+		return model.ErrorCannotBlockSelf
+	}
+
+	return bs.BlockRepository.Add(model.Block{
+		BlockerID: userID,
+		BlockedID: user.ID,
+	})
+}
+
+func (bs *BlockService) Unblock(username string, userID string) error {
+	user, err := bs.getUser(username)
+	if err != nil {
+		return err
+	}
+
+	return bs.BlockRepository.Delete(userID, user.ID)
+}
+
+func (bs *BlockService) Mute(username string, userID string) error {
+	user, err := bs.getUser(username)
+	if err != nil {
+		return err
+	}
+
+	if user.ID == userID {
+		return model.ErrorCannotMuteSelf
+	}
+
+	return bs.MuteRepository.Add(model.Mute{
+		MuterID: userID,
+		MutedID: user.ID,
+	})
+}
+
+func (bs *BlockService) Unmute(username string, userID string) error {
+	user, err := bs.getUser(username)
+	if err != nil {
+		return err
+	}
+
+	return bs.MuteRepository.Delete(userID, user.ID)
+}
+
+func (bs *BlockService) GetBlocked(userID string, query model.PageQuery) (model.UserRelationListResponse, error) {
+	query = query.Normalize()
+
+	users, err := bs.BlockRepository.FindBlocked(userID, query.Offset(), query.Limit)
+	if err != nil {
+		return model.UserRelationListResponse{}, err
+	}
+
+	total, err := bs.BlockRepository.CountBlocked(userID)
+	if err != nil {
+		return model.UserRelationListResponse{}, err
+	}
+
+	return model.UserRelationListResponse{
+		Users: users,
+		Page: model.PageResponse{
+			Page:  query.Page,
+			Limit: query.Limit,
+			Total: total,
+		},
+	}, nil
+}
+
+func (bs *BlockService) GetMuted(userID string, query model.PageQuery) (model.UserRelationListResponse, error) {
+	query = query.Normalize()
+
+	users, err := bs.MuteRepository.FindMuted(userID, query.Offset(), query.Limit)
+	if err != nil {
+		return model.UserRelationListResponse{}, err
+	}
+
+	total, err := bs.MuteRepository.CountMuted(userID)
+	if err != nil {
+		return model.UserRelationListResponse{}, err
+	}
+
+	return model.UserRelationListResponse{
+		Users: users,
+		Page: model.PageResponse{
+			Page:  query.Page,
+			Limit: query.Limit,
+			Total: total,
+		},
+	}, nil
+}
+
+func (bs *BlockService) getUser(username string) (model.User, error) {
+	user, err := bs.UserRepository.GetByUsername(username)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.User{}, err
+		}
+		return model.User{}, model.ErrorNotFound
+	}
+	return user, nil
+}
+
+// hiddenUserIDs are the users whose photos and comments are left out of the
+// viewer's listings: blocks in either direction and the viewer's mutes.
+func hiddenUserIDs(blockRepository repository.IBlockRepository, muteRepository repository.IMuteRepository, viewerID string) ([]string, error) {
+	blocked, err := blockRepository.FindBlockedUserIDs(viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	muted, err := muteRepository.FindMutedUserIDs(viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(blocked, muted...), nil
+}
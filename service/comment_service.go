@@ -8,26 +8,37 @@ import (
 
 type iCommentService interface {
 	CreateComment(request model.CommentCreateRequest, userID string, photoID string) (*model.CommentCreateResponse, error)
-	GetAll() ([]model.CommentResponse, error)
-	Update(UpdateComment model.CommentUpdateRequest, CommentID string, userID string) (model.CommentUpdateResponse, error)
-	GetOne(commentID string) (model.CommentResponse, error)
-	Delete(commentID string, userID string) error
+	GetAll(viewerID string) ([]model.CommentResponse, error)
+	Update(UpdateComment model.CommentUpdateRequest, CommentID string, userID string, role string) (model.CommentUpdateResponse, error)
+	GetOne(commentID string, viewerID string) (model.CommentResponse, error)
+	Delete(commentID string, userID string, role string) error
 }
 
 type CommentService struct {
 	CommentRepository repository.ICommentRepository
 	PhotoRepository   repository.IPhotoRepository
+	BlockRepository   repository.IBlockRepository
+	MuteRepository    repository.IMuteRepository
+	FollowRepository  repository.IFollowRepository
+	UserRepository    repository.IUserRepository
 }
 
-func NewCommentService(commentRepository repository.ICommentRepository, PhotoReposit repository.IPhotoRepository) *CommentService {
+func NewCommentService(commentRepository repository.ICommentRepository, PhotoReposit repository.IPhotoRepository, blockRepository repository.IBlockRepository, muteRepository repository.IMuteRepository, followRepository repository.IFollowRepository, userRepository repository.IUserRepository) *CommentService {
 	return &CommentService{
 		CommentRepository: commentRepository,
 		PhotoRepository:   PhotoReposit,
+		BlockRepository:   blockRepository,
+		MuteRepository:    muteRepository,
+		FollowRepository:  followRepository,
+		UserRepository:    userRepository,
 	}
 }
 
+// CreateComment refuses photos whose owner and the commenter blocked each
+// other and mature photos for commenters too young, the photo looks missing
+// to them, and photos of private accounts the commenter does not follow.
 func (cs *CommentService) CreateComment(request model.CommentCreateRequest, userID string, photoID string) (*model.CommentCreateResponse, error) {
-	_, err := cs.PhotoRepository.GetOne(photoID)
+	photo, err := cs.PhotoRepository.GetOne(photoID)
 	if err != nil {
 		if err != model.ErrorNotFound {
 			return &model.CommentCreateResponse{}, err
@@ -35,6 +46,24 @@ func (cs *CommentService) CreateComment(request model.CommentCreateRequest, user
 		return &model.CommentCreateResponse{}, model.ErrorNotFound
 	}
 
+	err = checkContentRating(cs.UserRepository, photo, userID)
+	if err != nil {
+		return &model.CommentCreateResponse{}, err
+	}
+
+	blocked, err := cs.BlockRepository.IsBlocked(userID, photo.UserID)
+	if err != nil {
+		return &model.CommentCreateResponse{}, err
+	}
+	if blocked {
+		return &model.CommentCreateResponse{}, model.ErrorNotFound
+	}
+
+	err = checkVisible(cs.FollowRepository, photo.UserID, userID)
+	if err != nil {
+		return &model.CommentCreateResponse{}, err
+	}
+
 	commentID := helper.GenerateID()
 
 	NewComment := model.Comment{
@@ -62,10 +91,23 @@ func (cs *CommentService) CreateComment(request model.CommentCreateRequest, user
 
 }
 
-func (cs *CommentService) GetAll() ([]model.CommentResponse, error) {
+// GetAll leaves out comments by users hidden from the viewer and comments on
+// their photos, the same goes for private accounts the viewer does not follow.
+// Comments on mature photos are left out for viewers too young for them.
+func (cs *CommentService) GetAll(viewerID string) ([]model.CommentResponse, error) {
 	AllComment := []model.CommentResponse{}
 
-	res, err := cs.CommentRepository.Get()
+	hidden, err := hiddenUserIDs(cs.BlockRepository, cs.MuteRepository, viewerID)
+	if err != nil {
+		return []model.CommentResponse{}, err
+	}
+
+	mature, err := canViewMature(cs.UserRepository, viewerID)
+	if err != nil {
+		return []model.CommentResponse{}, err
+	}
+
+	res, err := cs.CommentRepository.Get(viewerID, hidden, mature)
 	if err != nil {
 		return []model.CommentResponse{}, err
 	}
@@ -83,7 +125,7 @@ func (cs *CommentService) GetAll() ([]model.CommentResponse, error) {
 	return AllComment, nil
 
 }
-func (cs *CommentService) Update(UpdateComment model.CommentUpdateRequest, CommentID string, userID string) (model.CommentUpdateResponse, error) {
+func (cs *CommentService) Update(UpdateComment model.CommentUpdateRequest, CommentID string, userID string, role string) (model.CommentUpdateResponse, error) {
 	getID, err := cs.CommentRepository.GetOne(CommentID)
 	if err != nil {
 		if err != model.ErrorNotFound {
@@ -92,7 +134,7 @@ func (cs *CommentService) Update(UpdateComment model.CommentUpdateRequest, Comme
 		return model.CommentUpdateResponse{}, model.ErrorNotFound
 	}
 
-	if getID.UserID != userID {
+	if getID.UserID != userID && !model.CanModerate(role) {
 		return model.CommentUpdateResponse{}, model.ErrorForbiddenAccess
 	}
 
@@ -117,7 +159,11 @@ func (cs *CommentService) Update(UpdateComment model.CommentUpdateRequest, Comme
 
 }
 
-func (cs *CommentService) GetOne(commentID string) (model.CommentResponse, error) {
+// GetOne hides the comment when the viewer blocked or was blocked by its
+// author or the owner of the photo, or when the photo is mature and the viewer
+// too young. It refuses the comment when the author or the photo owner is a
+// private account the viewer does not follow.
+func (cs *CommentService) GetOne(commentID string, viewerID string) (model.CommentResponse, error) {
 	getOne, err := cs.CommentRepository.GetOne(commentID)
 
 	if err != nil {
@@ -128,6 +174,39 @@ func (cs *CommentService) GetOne(commentID string) (model.CommentResponse, error
 		return model.CommentResponse{}, model.ErrorNotFound
 	}
 
+	photo, err := cs.PhotoRepository.GetOne(getOne.PhotoID)
+	if err != nil && err != model.ErrorNotFound {
+		return model.CommentResponse{}, err
+	}
+
+	err = checkContentRating(cs.UserRepository, photo, viewerID)
+	if err != nil {
+		return model.CommentResponse{}, err
+	}
+
+	for _, ownerID := range []string{getOne.UserID, photo.UserID} {
+		if ownerID == "" {
+			continue
+		}
+		blocked, err := cs.BlockRepository.IsBlocked(viewerID, ownerID)
+		if err != nil {
+			return model.CommentResponse{}, err
+		}
+		if blocked {
+			return model.CommentResponse{}, model.ErrorNotFound
+		}
+	}
+
+	for _, ownerID := range []string{getOne.UserID, photo.UserID} {
+		if ownerID == "" {
+			continue
+		}
+		err = checkVisible(cs.FollowRepository, ownerID, viewerID)
+		if err != nil {
+			return model.CommentResponse{}, err
+		}
+	}
+
 	return model.CommentResponse{
 		CommentID: getOne.CommentID,
 		Message:   getOne.Message,
@@ -138,7 +217,9 @@ func (cs *CommentService) GetOne(commentID string) (model.CommentResponse, error
 	}, nil
 }
 
-func (cs *CommentService) Delete(commentID string, userID string) error {
+// Delete moves the comment to the trash. A comment removed by a moderator is
+// marked with the moderator's ID, so it stays out of the author's trash.
+func (cs *CommentService) Delete(commentID string, userID string, role string) error {
 	getCommentID, err := cs.CommentRepository.GetOne(commentID)
 
 	if err != nil {
@@ -149,11 +230,11 @@ func (cs *CommentService) Delete(commentID string, userID string) error {
 		return model.ErrorNotFound
 	}
 
-	if getCommentID.UserID != userID {
+	if getCommentID.UserID != userID && !model.CanModerate(role) {
 		return model.ErrorForbiddenAccess
 	}
 
-	err = cs.CommentRepository.Delete(commentID)
+	err = cs.CommentRepository.Delete(commentID, userID)
 
 	if err != nil {
 		return err
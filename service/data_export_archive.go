@@ -0,0 +1,119 @@
+package service
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"finalProject/model"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// writeDataExportArchive writes the content as a ZIP with a JSON and a CSV
+// file for each part.
+func writeDataExportArchive(w io.Writer, content model.DataExportContent) error {
+	zw := zip.NewWriter(w)
+
+	profile := content.Profile
+	err := writeArchiveFiles(zw, "profile", profile,
+		[]string{"id", "username", "email", "email_verified", "two_factor_enabled", "age", "role", "is_private", "created_at", "updated_at"},
+		[][]string{{profile.ID, profile.Username, profile.Email, strconv.FormatBool(profile.EmailVerified), strconv.FormatBool(profile.TwoFactor),
+			strconv.Itoa(profile.Age), profile.Role, strconv.FormatBool(profile.IsPrivate), formatArchiveTime(profile.CreatedAt), formatArchiveTime(profile.UpdatedAt)}})
+	if err != nil {
+		return err
+	}
+
+	photoRows := [][]string{}
+	for _, photo := range content.Photos {
+		photoRows = append(photoRows, []string{photo.PhotoID, photo.Title, photo.PhotoUrl, photo.ContentRating, formatArchiveTime(photo.CreatedAt), formatArchiveTime(photo.UpdatedAt)})
+	}
+	err = writeArchiveFiles(zw, "photos", content.Photos,
+		[]string{"photo_id", "title", "photo_url", "content_rating", "created_at", "updated_at"}, photoRows)
+	if err != nil {
+		return err
+	}
+
+	commentHeader := []string{"comment_id", "photo_id", "user_id", "message", "created_at", "updated_at"}
+	err = writeArchiveFiles(zw, "comments_written", content.CommentsWritten, commentHeader, commentRows(content.CommentsWritten))
+	if err != nil {
+		return err
+	}
+
+	err = writeArchiveFiles(zw, "comments_received", content.CommentsReceived, commentHeader, commentRows(content.CommentsReceived))
+	if err != nil {
+		return err
+	}
+
+	socialRows := [][]string{}
+	for _, socialMedia := range content.SocialMedias {
+		socialRows = append(socialRows, []string{socialMedia.SocialID, socialMedia.Name, socialMedia.SocialMediaUrl, formatArchiveTime(socialMedia.CreatedAt), formatArchiveTime(socialMedia.UpdatedAt)})
+	}
+	err = writeArchiveFiles(zw, "social_medias", content.SocialMedias,
+		[]string{"social_id", "name", "social_media_url", "created_at", "updated_at"}, socialRows)
+	if err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func commentRows(comments []model.CommentResponse) [][]string {
+	rows := [][]string{}
+	for _, comment := range comments {
+		rows = append(rows, []string{comment.CommentID, comment.PhotoID, comment.UserID, comment.Message, formatArchiveTime(comment.CreatedAt), formatArchiveTime(comment.UpdatedAt)})
+	}
+	return rows
+}
+
+// writeArchiveFiles adds name.json with the value and name.csv with the rows.
+func writeArchiveFiles(zw *zip.Writer, name string, value interface{}, header []string, rows [][]string) error {
+	jsonFile, err := zw.Create(name + ".json")
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(jsonFile)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(value)
+	if err != nil {
+		return err
+	}
+
+	csvFile, err := zw.Create(name + ".csv")
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(csvFile)
+	err = writer.Write(header)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = escapeFormula(cell)
+		}
+		err = writer.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// escapeFormula prefixes cells a spreadsheet would run as a formula with a
+// quote. Titles and messages come from other users too, so a comment could
+// otherwise plant a formula in the export of the photo owner.
+func escapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+func formatArchiveTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,324 @@
+package service
+
+import (
+	"errors"
+	"finalProject/helper"
+	"finalProject/model"
+	"finalProject/repository"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var (
+	// DATA_EXPORT_DIR is where the archives are kept, a folder in the system
+	// temp directory when unset. Any server may build an archive and any may
+	// serve its download, so with more than one server it must point to
+	// storage they all share, such as an NFS mount.
+	DATA_EXPORT_DIR = os.Getenv("DATA_EXPORT_DIR")
+	// DATA_EXPORT_LINK_DURATION is how long an archive can be downloaded.
+	DATA_EXPORT_LINK_DURATION = helper.GetEnvDuration("DATA_EXPORT_LINK_DURATION", 24*time.Hour)
+	// DATA_EXPORT_POLL_INTERVAL is how often the worker looks for work
+	// without being woken up.
+	DATA_EXPORT_POLL_INTERVAL = helper.GetEnvDuration("DATA_EXPORT_POLL_INTERVAL", time.Minute)
+)
+
+// dataExportStaleAfter is how long an export may stay processing before the
+// worker assumes its server stopped and builds it again.
+const dataExportStaleAfter = 30 * time.Minute
+
+type IDataExportService interface {
+	Request(userID string) (model.DataExportResponse, error)
+	GetStatus(exportID string, userID string) (model.DataExportResponse, error)
+	Download(exportID string, userID string) (model.DataExport, error)
+	Run()
+}
+
+type DataExportService struct {
+	DataExportRepository  repository.IDataExportRepository
+	UserRepository        repository.IUserRepository
+	PhotoRepository       repository.IPhotoRepository
+	CommentRepository     repository.ICommentRepository
+	SocialMediaRepository repository.ISocialMediaRepository
+	Dir                   string
+	wake                  chan struct{}
+}
+
+func NewDataExportService(dataExportRepository repository.IDataExportRepository, userRepository repository.IUserRepository, photoRepository repository.IPhotoRepository, commentRepository repository.ICommentRepository, socialMediaRepository repository.ISocialMediaRepository) *DataExportService {
+	dir := DATA_EXPORT_DIR
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "mygram-exports")
+	}
+
+	return &DataExportService{
+		DataExportRepository:  dataExportRepository,
+		UserRepository:        userRepository,
+		PhotoRepository:       photoRepository,
+		CommentRepository:     commentRepository,
+		SocialMediaRepository: socialMediaRepository,
+		Dir:                   dir,
+		wake:                  make(chan struct{}, 1),
+	}
+}
+
+// Request queues an export of the user's data. While an export is still
+// pending or processing that one is returned instead of queueing another.
+func (ds *DataExportService) Request(userID string) (model.DataExportResponse, error) {
+	active, err := ds.DataExportRepository.FindActiveByUser(userID)
+	if err == nil {
+		return toDataExportResponse(active), nil
+	}
+	if err != model.ErrorNotFound {
+		return model.DataExportResponse{}, err
+	}
+
+	export := model.DataExport{
+		ID:        helper.GenerateID(),
+		UserID:    userID,
+		Status:    model.DataExportPending,
+		CreatedAt: time.Now(),
+	}
+
+	err = ds.DataExportRepository.Add(export)
+	if err != nil {
+		return model.DataExportResponse{}, err
+	}
+
+	select {
+	case ds.wake <- struct{}{}:
+	default:
+	}
+
+	return toDataExportResponse(export), nil
+}
+
+// GetStatus reports ErrorNotFound for exports of other users.
+func (ds *DataExportService) GetStatus(exportID string, userID string) (model.DataExportResponse, error) {
+	export, err := ds.DataExportRepository.GetByID(exportID)
+	if err != nil {
+		return model.DataExportResponse{}, err
+	}
+
+	if export.UserID != userID {
+		return model.DataExportResponse{}, model.ErrorNotFound
+	}
+
+	return toDataExportResponse(export), nil
+}
+
+// Download returns the export when its archive can still be downloaded.
+func (ds *DataExportService) Download(exportID string, userID string) (model.DataExport, error) {
+	export, err := ds.DataExportRepository.GetByID(exportID)
+	if err != nil {
+		return model.DataExport{}, err
+	}
+
+	if export.UserID != userID {
+		return model.DataExport{}, model.ErrorNotFound
+	}
+
+	if export.Status == model.DataExportExpired || (export.ExpiresAt != nil && time.Now().After(*export.ExpiresAt)) {
+		return model.DataExport{}, model.ErrorExportExpired
+	}
+
+	if export.Status != model.DataExportReady {
+		return model.DataExport{}, model.ErrorExportNotReady
+	}
+
+	return export, nil
+}
+
+// Run builds the queued exports and removes expired archives until the
+// process exits. It wakes up on every request and every
+// DATA_EXPORT_POLL_INTERVAL, which picks up exports left behind by a restart
+// and, with a shared DATA_EXPORT_DIR, exports queued on another server.
+func (ds *DataExportService) Run() {
+	ticker := time.NewTicker(DATA_EXPORT_POLL_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		ds.processQueued()
+		ds.removeExpired()
+
+		select {
+		case <-ds.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (ds *DataExportService) processQueued() {
+	for {
+		export, err := ds.DataExportRepository.ClaimNext(time.Now().Add(-dataExportStaleAfter))
+		if err != nil {
+			if err != model.ErrorNotFound {
+				log.Printf("claim data export: %v", err)
+			}
+			return
+		}
+
+		filePath, err := ds.build(export)
+		if err != nil {
+			log.Printf("build data export %s: %v", export.ID, err)
+			err = ds.DataExportRepository.Fail(export.ID)
+			if err != nil {
+				log.Printf("mark data export %s failed: %v", export.ID, err)
+			}
+			continue
+		}
+
+		now := time.Now()
+		err = ds.DataExportRepository.Complete(export.ID, filePath, now, now.Add(DATA_EXPORT_LINK_DURATION))
+		if err != nil {
+			log.Printf("complete data export %s: %v", export.ID, err)
+		}
+	}
+}
+
+// build writes the archive next to its final name first so a download never
+// sees a half written file.
+func (ds *DataExportService) build(export model.DataExport) (string, error) {
+	content, err := ds.collect(export.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(ds.Dir, 0o700)
+	if err != nil {
+		return "", err
+	}
+
+	filePath := filepath.Join(ds.Dir, export.ID+".zip")
+	tmpPath := filePath + ".tmp"
+
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+
+	err = writeDataExportArchive(file, content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+
+	return filePath, os.Rename(tmpPath, filePath)
+}
+
+func (ds *DataExportService) collect(userID string) (model.DataExportContent, error) {
+	user, err := ds.UserRepository.GetByID(userID)
+	if err != nil {
+		return model.DataExportContent{}, err
+	}
+
+	photos, err := ds.PhotoRepository.FindByUser(userID)
+	if err != nil {
+		return model.DataExportContent{}, err
+	}
+
+	written, err := ds.CommentRepository.FindByUser(userID)
+	if err != nil {
+		return model.DataExportContent{}, err
+	}
+
+	received, err := ds.CommentRepository.FindReceivedByUser(userID)
+	if err != nil {
+		return model.DataExportContent{}, err
+	}
+
+	socialMedias, err := ds.SocialMediaRepository.FindByUser(userID)
+	if err != nil {
+		return model.DataExportContent{}, err
+	}
+
+	content := model.DataExportContent{
+		Profile:          toUserResponse(user),
+		Photos:           []model.PhotoAllResponse{},
+		CommentsWritten:  toCommentResponses(written),
+		CommentsReceived: toCommentResponses(received),
+		SocialMedias:     []model.SocialMediaResponse{},
+	}
+
+	for _, photo := range photos {
+		content.Photos = append(content.Photos, model.PhotoAllResponse{
+			PhotoID:       photo.PhotoID,
+			Title:         photo.Title,
+			PhotoUrl:      photo.PhotoUrl,
+			ContentRating: photo.ContentRating,
+			UserID:        photo.UserID,
+			CreatedAt:     photo.CreatedAt,
+			UpdatedAt:     photo.UpdatedAt,
+		})
+	}
+
+	for _, socialMedia := range socialMedias {
+		content.SocialMedias = append(content.SocialMedias, model.SocialMediaResponse{
+			SocialID:       socialMedia.SocialID,
+			Name:           socialMedia.Name,
+			SocialMediaUrl: socialMedia.SocialMediaUrl,
+			UserID:         socialMedia.UserID,
+			CreatedAt:      socialMedia.CreatedAt,
+			UpdatedAt:      socialMedia.UpdatedAt,
+		})
+	}
+
+	return content, nil
+}
+
+// removeExpired deletes the archives whose link expired or whose user was
+// deleted.
+func (ds *DataExportService) removeExpired() {
+	exports, err := ds.DataExportRepository.FindExpired(time.Now())
+	if err != nil {
+		log.Printf("find expired data exports: %v", err)
+		return
+	}
+
+	for _, export := range exports {
+		if export.FilePath != "" {
+			err = os.Remove(export.FilePath)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				log.Printf("remove data export %s: %v", export.ID, err)
+				continue
+			}
+		}
+
+		err = ds.DataExportRepository.MarkExpired(export.ID)
+		if err != nil {
+			log.Printf("mark data export %s expired: %v", export.ID, err)
+		}
+	}
+}
+
+func toCommentResponses(comments []model.Comment) []model.CommentResponse {
+	responses := []model.CommentResponse{}
+	for _, comment := range comments {
+		responses = append(responses, model.CommentResponse{
+			CommentID: comment.CommentID,
+			Message:   comment.Message,
+			UserID:    comment.UserID,
+			PhotoID:   comment.PhotoID,
+			CreatedAt: comment.CreatedAt,
+			UpdatedAt: comment.UpdatedAt,
+		})
+	}
+	return responses
+}
+
+func toDataExportResponse(export model.DataExport) model.DataExportResponse {
+	response := model.DataExportResponse{
+		ID:          export.ID,
+		Status:      export.Status,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+	if export.Status == model.DataExportReady {
+		response.DownloadURL = "/mygram/user/exports/" + export.ID + "/download"
+	}
+	return response
+}
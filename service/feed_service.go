@@ -0,0 +1,199 @@
+package service
+
+import (
+	"encoding/base64"
+	"finalProject/helper"
+	"finalProject/model"
+	"finalProject/repository"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// FEED_FANOUT_MAX_FOLLOWERS is the follower count above which a new photo
+	// is no longer copied into every follower's feed but read on demand.
+	FEED_FANOUT_MAX_FOLLOWERS = helper.GetEnvInt("FEED_FANOUT_MAX_FOLLOWERS", 10000)
+	// FEED_BACKFILL_PHOTOS is how many earlier photos of a user are added to
+	// the feed of someone who starts following them.
+	FEED_BACKFILL_PHOTOS = helper.GetEnvInt("FEED_BACKFILL_PHOTOS", 20)
+)
+
+type IFeedService interface {
+	GetFeed(userID string, query model.CursorQuery) (model.FeedResponse, error)
+}
+
+type FeedService struct {
+	FeedRepository  repository.IFeedRepository
+	PhotoRepository repository.IPhotoRepository
+	BlockRepository repository.IBlockRepository
+	MuteRepository  repository.IMuteRepository
+	UserRepository  repository.IUserRepository
+}
+
+func NewFeedService(feedRepository repository.IFeedRepository, photoRepository repository.IPhotoRepository, blockRepository repository.IBlockRepository, muteRepository repository.IMuteRepository, userRepository repository.IUserRepository) *FeedService {
+	return &FeedService{
+		FeedRepository:  feedRepository,
+		PhotoRepository: photoRepository,
+		BlockRepository: blockRepository,
+		MuteRepository:  muteRepository,
+		UserRepository:  userRepository,
+	}
+}
+
+// GetFeed returns the photos of the followed users and the user's own photos,
+// newest first. Photos fanned out on posting come from the feed items, the
+// user's own photos and those of followed users with too many followers to
+// fan out to are read from the photos table and merged in. Mature photos are
+// left out for users too young for them.
+func (fs *FeedService) GetFeed(userID string, query model.CursorQuery) (model.FeedResponse, error) {
+	query = query.Normalize()
+
+	cursor, err := decodeFeedCursor(query.Cursor)
+	if err != nil {
+		return model.FeedResponse{}, err
+	}
+
+	hidden, err := hiddenUserIDs(fs.BlockRepository, fs.MuteRepository, userID)
+	if err != nil {
+		return model.FeedResponse{}, err
+	}
+
+	mature, err := canViewMature(fs.UserRepository, userID)
+	if err != nil {
+		return model.FeedResponse{}, err
+	}
+
+	// one extra photo tells whether there is a next page
+	fannedOut, err := fs.FeedRepository.Find(userID, cursor, hidden, query.Limit+1, mature)
+	if err != nil {
+		return model.FeedResponse{}, err
+	}
+
+	pullAuthors, err := fs.FeedRepository.FindPullAuthors(userID)
+	if err != nil {
+		return model.FeedResponse{}, err
+	}
+
+	pulled, err := fs.PhotoRepository.FindByUsers(append(withoutUserIDs(pullAuthors, hidden), userID), cursor, query.Limit+1, mature)
+	if err != nil {
+		return model.FeedResponse{}, err
+	}
+
+	photos := mergeFeedPhotos(fannedOut, pulled)
+
+	response := model.FeedResponse{
+		Photos: []model.PhotoAllResponse{},
+		Page: model.CursorResponse{
+			Limit: query.Limit,
+		},
+	}
+	if len(photos) > query.Limit {
+		photos = photos[:query.Limit]
+		last := photos[len(photos)-1]
+		response.Page.NextCursor = encodeFeedCursor(model.FeedCursor{
+			CreatedAt: last.CreatedAt,
+			PhotoID:   last.PhotoID,
+		})
+	}
+
+	for _, photo := range photos {
+		response.Photos = append(response.Photos, model.PhotoAllResponse{
+			PhotoID:       photo.PhotoID,
+			Title:         photo.Title,
+			PhotoUrl:      photo.PhotoUrl,
+			ContentRating: photo.ContentRating,
+			UserID:        photo.UserID,
+			CreatedAt:     photo.CreatedAt,
+			UpdatedAt:     photo.UpdatedAt,
+		})
+	}
+
+	return response, nil
+}
+
+// fanOutPhoto adds a new photo to the feed of the author's followers, or marks
+// the author to be read on demand when they have too many followers.
+func fanOutPhoto(feedRepository repository.IFeedRepository, followRepository repository.IFollowRepository, photo model.Photo) error {
+	followers, err := followRepository.CountFollowers(photo.UserID)
+	if err != nil {
+		return err
+	}
+
+	if followers > int64(FEED_FANOUT_MAX_FOLLOWERS) {
+		return feedRepository.AddPullAuthor(photo.UserID)
+	}
+
+	return feedRepository.FanOut(photo.PhotoID)
+}
+
+// mergeFeedPhotos merges two newest first lists, a photo can be in both when
+// its author became a pull author after it was fanned out.
+func mergeFeedPhotos(lists ...[]model.Photo) []model.Photo {
+	seen := map[string]bool{}
+	photos := []model.Photo{}
+	for _, list := range lists {
+		for _, photo := range list {
+			if seen[photo.PhotoID] {
+				continue
+			}
+			seen[photo.PhotoID] = true
+			photos = append(photos, photo)
+		}
+	}
+
+	sort.Slice(photos, func(i, j int) bool {
+		if !photos[i].CreatedAt.Equal(photos[j].CreatedAt) {
+			return photos[i].CreatedAt.After(photos[j].CreatedAt)
+		}
+		return photos[i].PhotoID > photos[j].PhotoID
+	})
+	return photos
+}
+
+func withoutUserIDs(userIDs []string, excluded []string) []string {
+	excludedSet := map[string]bool{}
+	for _, userID := range excluded {
+		excludedSet[userID] = true
+	}
+
+	kept := []string{}
+	for _, userID := range userIDs {
+		if !excludedSet[userID] {
+			kept = append(kept, userID)
+		}
+	}
+	return kept
+}
+
+func encodeFeedCursor(cursor model.FeedCursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + " " + cursor.PhotoID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeFeedCursor returns nil for an empty cursor, the first page.
+func decodeFeedCursor(encoded string) (*model.FeedCursor, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, model.ErrorInvalidCursor
+	}
+
+	createdAt, photoID, found := strings.Cut(string(raw), " ")
+	if !found || photoID == "" {
+		return nil, model.ErrorInvalidCursor
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, model.ErrorInvalidCursor
+	}
+
+	return &model.FeedCursor{
+		CreatedAt: parsed,
+		PhotoID:   photoID,
+	}, nil
+}
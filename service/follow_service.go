@@ -0,0 +1,289 @@
+package service
+
+import (
+	"errors"
+	"finalProject/model"
+	"finalProject/repository"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+type IFollowService interface {
+	Follow(username string, followerID string) (model.FollowStatusResponse, error)
+	Unfollow(username string, followerID string) error
+	GetFollowers(username string, viewerID string, query model.PageQuery) (model.FollowListResponse, error)
+	GetFollowing(username string, viewerID string, query model.PageQuery) (model.FollowListResponse, error)
+	GetFollowRequests(userID string, query model.PageQuery) (model.UserRelationListResponse, error)
+	AcceptFollowRequest(username string, userID string) error
+	RejectFollowRequest(username string, userID string) error
+	SetPrivacy(request model.UserPrivacyRequest, userID string) error
+}
+
+type FollowService struct {
+	UserRepository          repository.IUserRepository
+	FollowRepository        repository.IFollowRepository
+	FollowRequestRepository repository.IFollowRequestRepository
+	BlockRepository         repository.IBlockRepository
+	FeedRepository          repository.IFeedRepository
+}
+
+func NewFollowService(userRepository repository.IUserRepository, followRepository repository.IFollowRepository, followRequestRepository repository.IFollowRequestRepository, blockRepository repository.IBlockRepository, feedRepository repository.IFeedRepository) *FollowService {
+	return &FollowService{
+		UserRepository:          userRepository,
+		FollowRepository:        followRepository,
+		FollowRequestRepository: followRequestRepository,
+		BlockRepository:         blockRepository,
+		FeedRepository:          feedRepository,
+	}
+}
+
+// Follow is idempotent, following someone again changes nothing. Users who
+// blocked each other cannot follow each other. Following a private account
+// only sends a follow request, otherwise the latest photos of the followed
+// user are added to the follower's feed.
+func (fs *FollowService) Follow(username string, followerID string) (model.FollowStatusResponse, error) {
+	user, err := fs.getUser(username)
+	if err != nil {
+		return model.FollowStatusResponse{}, err
+	}
+
+	if user.ID == followerID {
+		return model.FollowStatusResponse{}, model.ErrorCannotFollowSelf
+	}
+
+	blocked, err := fs.BlockRepository.IsBlocked(followerID, user.ID)
+	if err != nil {
+		return model.FollowStatusResponse{}, err
+	}
+	if blocked {
+		return model.FollowStatusResponse{}, model.ErrorNotFound
+	}
+
+	following, err := fs.FollowRepository.IsFollowing(followerID, user.ID)
+	if err != nil {
+		return model.FollowStatusResponse{}, err
+	}
+	if following {
+		return model.FollowStatusResponse{Status: model.FollowStatusFollowing}, nil
+	}
+
+	if user.IsPrivate {
+		err = fs.FollowRequestRepository.Add(model.FollowRequest{
+			RequesterID: followerID,
+			TargetID:    user.ID,
+		})
+		if err != nil {
+			return model.FollowStatusResponse{}, err
+		}
+		return model.FollowStatusResponse{Status: model.FollowStatusRequested}, nil
+	}
+
+	err = fs.FollowRepository.Add(model.Follow{
+		FollowerID: followerID,
+		FolloweeID: user.ID,
+	})
+	if err != nil {
+		return model.FollowStatusResponse{}, err
+	}
+
+	fs.backfillFeed(followerID, user.ID)
+	return model.FollowStatusResponse{Status: model.FollowStatusFollowing}, nil
+}
+
+// Unfollow is idempotent, unfollowing someone not followed changes nothing.
+// It also withdraws a pending follow request. The unfollowed user's photos
+// leave the follower's feed.
+func (fs *FollowService) Unfollow(username string, followerID string) error {
+	user, err := fs.getUser(username)
+	if err != nil {
+		return err
+	}
+
+	err = fs.FollowRequestRepository.Delete(followerID, user.ID)
+	if err != nil {
+		return err
+	}
+
+	err = fs.FollowRepository.Delete(followerID, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return fs.FeedRepository.RemoveAuthor(followerID, user.ID)
+}
+
+// GetFollowers is refused for a private account the viewer does not follow.
+func (fs *FollowService) GetFollowers(username string, viewerID string, query model.PageQuery) (model.FollowListResponse, error) {
+	user, err := fs.getVisibleUser(username, viewerID)
+	if err != nil {
+		return model.FollowListResponse{}, err
+	}
+
+	query = query.Normalize()
+
+	users, err := fs.FollowRepository.FindFollowers(user.ID, query.Offset(), query.Limit)
+	if err != nil {
+		return model.FollowListResponse{}, err
+	}
+
+	total, err := fs.FollowRepository.CountFollowers(user.ID)
+	if err != nil {
+		return model.FollowListResponse{}, err
+	}
+
+	return model.FollowListResponse{
+		Users: users,
+		Page: model.PageResponse{
+			Page:  query.Page,
+			Limit: query.Limit,
+			Total: total,
+		},
+	}, nil
+}
+
+// GetFollowing is refused for a private account the viewer does not follow.
+func (fs *FollowService) GetFollowing(username string, viewerID string, query model.PageQuery) (model.FollowListResponse, error) {
+	user, err := fs.getVisibleUser(username, viewerID)
+	if err != nil {
+		return model.FollowListResponse{}, err
+	}
+
+	query = query.Normalize()
+
+	users, err := fs.FollowRepository.FindFollowing(user.ID, query.Offset(), query.Limit)
+	if err != nil {
+		return model.FollowListResponse{}, err
+	}
+
+	total, err := fs.FollowRepository.CountFollowing(user.ID)
+	if err != nil {
+		return model.FollowListResponse{}, err
+	}
+
+	return model.FollowListResponse{
+		Users: users,
+		Page: model.PageResponse{
+			Page:  query.Page,
+			Limit: query.Limit,
+			Total: total,
+		},
+	}, nil
+}
+
+// GetFollowRequests lists the pending requests to follow the user.
+func (fs *FollowService) GetFollowRequests(userID string, query model.PageQuery) (model.UserRelationListResponse, error) {
+	query = query.Normalize()
+
+	users, err := fs.FollowRequestRepository.FindPending(userID, query.Offset(), query.Limit)
+	if err != nil {
+		return model.UserRelationListResponse{}, err
+	}
+
+	total, err := fs.FollowRequestRepository.CountPending(userID)
+	if err != nil {
+		return model.UserRelationListResponse{}, err
+	}
+
+	return model.UserRelationListResponse{
+		Users: users,
+		Page: model.PageResponse{
+			Page:  query.Page,
+			Limit: query.Limit,
+			Total: total,
+		},
+	}, nil
+}
+
+// AcceptFollowRequest makes the requester a follower of the user.
+func (fs *FollowService) AcceptFollowRequest(username string, userID string) error {
+	requester, err := fs.getUser(username)
+	if err != nil {
+		return err
+	}
+
+	err = fs.FollowRequestRepository.Accept(requester.ID, userID)
+	if err != nil {
+		return err
+	}
+
+	fs.backfillFeed(requester.ID, userID)
+	return nil
+}
+
+func (fs *FollowService) RejectFollowRequest(username string, userID string) error {
+	requester, err := fs.getUser(username)
+	if err != nil {
+		return err
+	}
+
+	return fs.FollowRequestRepository.Reject(requester.ID, userID)
+}
+
+// SetPrivacy switches the account between public and private. Making the
+// account public accepts every pending follow request.
+func (fs *FollowService) SetPrivacy(request model.UserPrivacyRequest, userID string) error {
+	err := fs.UserRepository.SetPrivate(userID, request.IsPrivate)
+	if err != nil {
+		return err
+	}
+
+	if request.IsPrivate {
+		return nil
+	}
+
+	requesterIDs, err := fs.FollowRequestRepository.AcceptAll(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, requesterID := range requesterIDs {
+		fs.backfillFeed(requesterID, userID)
+	}
+	return nil
+}
+
+// backfillFeed only logs a failure, the follow itself already succeeded.
+func (fs *FollowService) backfillFeed(followerID string, followeeID string) {
+	err := fs.FeedRepository.Backfill(followerID, followeeID, FEED_BACKFILL_PHOTOS)
+	if err != nil {
+		log.Printf("backfill feed of %s with %s: %v", followerID, followeeID, err)
+	}
+}
+
+func (fs *FollowService) getUser(username string) (model.User, error) {
+	user, err := fs.UserRepository.GetByUsername(username)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.User{}, err
+		}
+		return model.User{}, model.ErrorNotFound
+	}
+	return user, nil
+}
+
+func (fs *FollowService) getVisibleUser(username string, viewerID string) (model.User, error) {
+	user, err := fs.getUser(username)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	err = checkVisible(fs.FollowRepository, user.ID, viewerID)
+	if err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+// checkVisible returns ErrorPrivateAccount when the owner has a private
+// account and the viewer is neither the owner nor an approved follower.
+func checkVisible(followRepository repository.IFollowRepository, ownerID string, viewerID string) error {
+	private, err := followRepository.IsPrivateTo(ownerID, viewerID)
+	if err != nil {
+		return err
+	}
+	if private {
+		return model.ErrorPrivateAccount
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"finalProject/helper"
+	"finalProject/model"
+	"finalProject/repository"
+	"strings"
+	"time"
+)
+
+// Login throttling configuration. Every failure of an account doubles the
+// wait before its next attempt, starting at LOGIN_BACKOFF_BASE. An account or
+// client IP is locked for LOGIN_LOCKOUT_DURATION once it reaches its failure
+// limit. Failures older than the lockout duration are forgotten.
+var (
+	LOGIN_MAX_FAILURES     = helper.GetEnvInt("LOGIN_MAX_FAILURES", 5)
+	LOGIN_IP_MAX_FAILURES  = helper.GetEnvInt("LOGIN_IP_MAX_FAILURES", 20)
+	LOGIN_BACKOFF_BASE     = helper.GetEnvDuration("LOGIN_BACKOFF_BASE", time.Second)
+	LOGIN_LOCKOUT_DURATION = helper.GetEnvDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute)
+)
+
+type LoginGuard struct {
+	LoginAttemptRepository repository.ILoginAttemptRepository
+	LoginLockoutRepository repository.ILoginLockoutRepository
+}
+
+func NewLoginGuard(loginAttemptRepository repository.ILoginAttemptRepository, loginLockoutRepository repository.ILoginLockoutRepository) *LoginGuard {
+	return &LoginGuard{
+		LoginAttemptRepository: loginAttemptRepository,
+		LoginLockoutRepository: loginLockoutRepository,
+	}
+}
+
+func accountKey(userID string) string {
+	return "account:" + userID
+}
+
+// unknownAccountKey throttles identifiers that do not match any user the same
+// way as real accounts, so the responses do not reveal which accounts exist.
+func unknownAccountKey(identifier string) string {
+	return "account:" + strings.ToLower(identifier)
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// CheckClient returns a TooManyAttemptsError while the client IP is locked.
+// Client IPs get no backoff, many users can share one behind a NAT and a few
+// of their failures must not hold all of them back.
+func (lg *LoginGuard) CheckClient(key string) error {
+	attempt, err := lg.LoginAttemptRepository.Get(key)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if now.Before(attempt.LockedUntil) {
+		return model.TooManyAttemptsError{RetryAfter: attempt.LockedUntil.Sub(now)}
+	}
+	return nil
+}
+
+// Check returns a TooManyAttemptsError when any of the account keys has to wait.
+func (lg *LoginGuard) Check(keys ...string) error {
+	now := time.Now()
+
+	for _, key := range keys {
+		attempt, err := lg.LoginAttemptRepository.Get(key)
+		if err != nil {
+			return err
+		}
+
+		if now.Before(attempt.LockedUntil) {
+			return model.TooManyAttemptsError{RetryAfter: attempt.LockedUntil.Sub(now)}
+		}
+
+		if attempt.Failures > 0 && now.Sub(attempt.LastFailure) <= LOGIN_LOCKOUT_DURATION {
+			nextAttempt := attempt.LastFailure.Add(backoff(attempt.Failures))
+			if now.Before(nextAttempt) {
+				return model.TooManyAttemptsError{RetryAfter: nextAttempt.Sub(now)}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Fail records a failed attempt for the account and the client IP and locks
+// whichever reached its limit.
+func (lg *LoginGuard) Fail(account string, ip string) error {
+	err := lg.fail(account, LOGIN_MAX_FAILURES)
+	if err != nil {
+		return err
+	}
+
+	return lg.fail(ip, LOGIN_IP_MAX_FAILURES)
+}
+
+func (lg *LoginGuard) fail(key string, maxFailures int) error {
+	attempt, err := lg.LoginAttemptRepository.RecordFailure(key, LOGIN_LOCKOUT_DURATION)
+	if err != nil {
+		return err
+	}
+
+	if attempt.Failures < maxFailures {
+		return nil
+	}
+
+	lockedUntil := time.Now().Add(LOGIN_LOCKOUT_DURATION)
+	err = lg.LoginAttemptRepository.Lock(key, lockedUntil)
+	if err != nil {
+		return err
+	}
+
+	return lg.LoginLockoutRepository.Add(model.LoginLockout{
+		ID:          helper.GenerateID(),
+		Key:         key,
+		Failures:    attempt.Failures,
+		LockedUntil: lockedUntil,
+	})
+}
+
+// Succeed clears the failures of an account after a successful login.
+func (lg *LoginGuard) Succeed(key string) error {
+	return lg.LoginAttemptRepository.Reset(key)
+}
+
+// Unlock clears the failures of a key and lifts its lockout if there is one.
+func (lg *LoginGuard) Unlock(key string) error {
+	err := lg.LoginAttemptRepository.Reset(key)
+	if err != nil {
+		return err
+	}
+
+	return lg.LoginLockoutRepository.MarkUnlocked(key)
+}
+
+func backoff(failures int) time.Duration {
+	if failures > 30 {
+		return LOGIN_LOCKOUT_DURATION
+	}
+
+	wait := LOGIN_BACKOFF_BASE << (failures - 1)
+	if wait > LOGIN_LOCKOUT_DURATION || wait <= 0 {
+		return LOGIN_LOCKOUT_DURATION
+	}
+	return wait
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"finalProject/helper"
+	"finalProject/model"
+	"finalProject/oidc"
+	"finalProject/repository"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	oidcStateDuration = 10 * time.Minute
+
+	maxUsernameAttempts = 5
+)
+
+type IOIDCService interface {
+	StartLogin() (string, string, error)
+	Callback(request model.OIDCCallbackRequest, cookieState string, client model.ClientInfo) (model.UserLoginResponse, error)
+}
+
+type OIDCService struct {
+	Provider               *oidc.Provider
+	UserRepository         repository.IUserRepository
+	UserIdentityRepository repository.IUserIdentityRepository
+	OIDCStateRepository    repository.IOIDCStateRepository
+	UserService            *UserService
+}
+
+func NewOIDCService(provider *oidc.Provider, userRepository repository.IUserRepository, userIdentityRepository repository.IUserIdentityRepository, oidcStateRepository repository.IOIDCStateRepository, userService *UserService) *OIDCService {
+	return &OIDCService{
+		Provider:               provider,
+		UserRepository:         userRepository,
+		UserIdentityRepository: userIdentityRepository,
+		OIDCStateRepository:    oidcStateRepository,
+		UserService:            userService,
+	}
+}
+
+// StartLogin returns the provider URL to send the user to and the state the
+// client has to present again on the callback.
+func (oc *OIDCService) StartLogin() (string, string, error) {
+	if oc.Provider == nil {
+		return "", "", model.ErrorOIDCNotConfigured
+	}
+
+	state, err := helper.GenerateRandomToken()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := helper.GenerateRandomToken()
+	if err != nil {
+		return "", "", err
+	}
+	codeVerifier, err := helper.GenerateRandomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	err = oc.OIDCStateRepository.Add(model.OIDCLoginState{
+		StateHash:    helper.HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    time.Now().Add(oidcStateDuration),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL, err := oc.Provider.AuthCodeURL(state, nonce, oidc.CodeChallenge(codeVerifier))
+	if err != nil {
+		return "", "", err
+	}
+
+	return authURL, state, nil
+}
+
+// Callback finishes the provider login. cookieState is the state stored in
+// the browser that started the login, which stops a callback URL from another
+// session from signing this browser in.
+func (oc *OIDCService) Callback(request model.OIDCCallbackRequest, cookieState string, client model.ClientInfo) (model.UserLoginResponse, error) {
+	if oc.Provider == nil {
+		return model.UserLoginResponse{}, model.ErrorOIDCNotConfigured
+	}
+
+	if request.State == "" || subtle.ConstantTimeCompare([]byte(request.State), []byte(cookieState)) != 1 {
+		return model.UserLoginResponse{}, model.ErrorInvalidOIDCState
+	}
+
+	state, err := oc.OIDCStateRepository.Consume(helper.HashToken(request.State))
+	if err != nil {
+		if err != model.ErrorNotFound {
+			return model.UserLoginResponse{}, err
+		}
+		return model.UserLoginResponse{}, model.ErrorInvalidOIDCState
+	}
+
+	if time.Now().After(state.ExpiresAt) {
+		return model.UserLoginResponse{}, model.ErrorInvalidOIDCState
+	}
+
+	if request.Error != "" || request.Code == "" {
+		log.Printf("oidc provider returned error %q: %s", request.Error, request.ErrorDescription)
+		return model.UserLoginResponse{}, model.ErrorOIDCLoginFailed
+	}
+
+	rawIDToken, err := oc.Provider.Exchange(request.Code, state.CodeVerifier)
+	if err != nil {
+		log.Printf("oidc code exchange: %v", err)
+		return model.UserLoginResponse{}, model.ErrorOIDCLoginFailed
+	}
+
+	claims, err := oc.Provider.VerifyIDToken(rawIDToken, state.Nonce)
+	if err != nil {
+		log.Printf("oidc id token: %v", err)
+		return model.UserLoginResponse{}, model.ErrorOIDCLoginFailed
+	}
+
+	user, err := oc.resolveUser(claims)
+	if err != nil {
+		return model.UserLoginResponse{}, err
+	}
+
+	if REQUIRE_EMAIL_VERIFICATION && user.EmailVerifiedAt == nil {
+		return model.UserLoginResponse{}, model.ErrorEmailNotVerified
+	}
+
+	if user.TOTPEnabledAt != nil {
+		return loginChallenge(user.ID)
+	}
+
+	return oc.UserService.startSession(user, client)
+}
+
+// resolveUser finds the user linked to the provider account. An unknown
+// account is linked to an existing user only when both sides verified the
+// same email, otherwise a new user is created. A linked user in the trash
+// cannot sign in until an admin restores the account.
+func (oc *OIDCService) resolveUser(claims oidc.Claims) (model.User, error) {
+	identity, err := oc.UserIdentityRepository.Get(claims.Issuer, claims.Subject)
+	if err == nil {
+		user, err := oc.UserRepository.GetByID(identity.UserID)
+		if err == model.ErrorNotFound {
+			return model.User{}, model.ErrorAccountDeleted
+		}
+		return user, err
+	}
+	if err != model.ErrorNotFound {
+		return model.User{}, err
+	}
+
+	if claims.Email == "" {
+		return model.User{}, model.ErrorOIDCEmailRequired
+	}
+
+	newIdentity := model.UserIdentity{
+		ID:      helper.GenerateID(),
+		Issuer:  claims.Issuer,
+		Subject: claims.Subject,
+		Email:   claims.Email,
+	}
+
+	existing, err := oc.UserRepository.GetByEmail(claims.Email)
+	if err == nil {
+		if !claims.EmailVerified || existing.EmailVerifiedAt == nil {
+			return model.User{}, model.ErrorOIDCAccountExists
+		}
+
+		newIdentity.UserID = existing.ID
+		err = oc.UserIdentityRepository.Add(newIdentity)
+		if err != nil {
+			return model.User{}, err
+		}
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.User{}, err
+	}
+
+	username, err := oc.availableUsername(claims)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	// the account has no password until the user sets one through the reset
+	// flow, and the age is unknown until the user fills it in, so it starts
+	// with the restricted defaults
+	newUser := model.User{
+		ID:        helper.GenerateID(),
+		Username:  username,
+		Email:     claims.Email,
+		Role:      model.RoleUser,
+		IsPrivate: isRestrictedAge(0),
+	}
+	if claims.EmailVerified {
+		now := time.Now()
+		newUser.EmailVerifiedAt = &now
+	}
+	newIdentity.UserID = newUser.ID
+
+	created, err := oc.UserIdentityRepository.AddWithUser(newUser, newIdentity)
+	if err == model.ErrorEmailAlreadyExists {
+		return model.User{}, model.ErrorOIDCAccountExists
+	}
+	return created, err
+}
+
+// availableUsername derives a username from the provider claims and adds a
+// number when it is taken.
+func (oc *OIDCService) availableUsername(claims oidc.Claims) (string, error) {
+	base := sanitizeUsername(claims.PreferredUsername)
+	if base == "" {
+		local, _, _ := strings.Cut(claims.Email, "@")
+		base = sanitizeUsername(local)
+	}
+	if base == "" {
+		base = "user"
+	}
+
+	username := base
+	for i := 0; i < maxUsernameAttempts; i++ {
+		_, err := oc.UserRepository.GetByUsername(username)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return username, nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		suffix, err := rand.Int(rand.Reader, big.NewInt(10000))
+		if err != nil {
+			return "", err
+		}
+		username = fmt.Sprintf("%s%04d", base, suffix.Int64())
+	}
+
+	return "", model.ErrorUsernameAlreadyExists
+}
+
+func sanitizeUsername(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '.' {
+			b.WriteRune(r)
+		}
+	}
+
+	username := b.String()
+	if len(username) > 30 {
+		username = username[:30]
+	}
+	return username
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"errors"
+	"finalProject/helper"
+	"finalProject/mailer"
+	"finalProject/model"
+	"finalProject/repository"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PASSWORD_RESET_URL is the page of the client app that accepts the reset token,
+// the token is appended as the "token" query parameter.
+var PASSWORD_RESET_URL = os.Getenv("PASSWORD_RESET_URL")
+
+type IPasswordService interface {
+	ChangePassword(request model.UserChangePasswordRequest, userID string) error
+	ForgotPassword(request model.UserForgotPasswordRequest) error
+	ResetPassword(request model.UserResetPasswordRequest) error
+}
+
+type PasswordService struct {
+	UserRepository          repository.IUserRepository
+	PasswordResetRepository repository.IPasswordResetRepository
+	RefreshTokenRepository  repository.IRefreshTokenRepository
+	RevocationRepository    repository.IRevocationRepository
+	SessionRepository       repository.ISessionRepository
+	TokenRepository         repository.IPersonalAccessTokenRepository
+	Mailer                  mailer.Mailer
+	LoginGuard              *LoginGuard
+}
+
+func NewPasswordService(userRepository repository.IUserRepository, passwordResetRepository repository.IPasswordResetRepository, refreshTokenRepository repository.IRefreshTokenRepository, revocationRepository repository.IRevocationRepository, sessionRepository repository.ISessionRepository, tokenRepository repository.IPersonalAccessTokenRepository, mail mailer.Mailer, loginGuard *LoginGuard) *PasswordService {
+	return &PasswordService{
+		UserRepository:          userRepository,
+		PasswordResetRepository: passwordResetRepository,
+		RefreshTokenRepository:  refreshTokenRepository,
+		RevocationRepository:    revocationRepository,
+		SessionRepository:       sessionRepository,
+		TokenRepository:         tokenRepository,
+		Mailer:                  mail,
+		LoginGuard:              loginGuard,
+	}
+}
+
+// ChangePassword replaces the password after checking the old one and signs the
+// user out of every device.
+func (ps *PasswordService) ChangePassword(request model.UserChangePasswordRequest, userID string) error {
+	user, err := ps.UserRepository.GetByID(userID)
+	if err != nil {
+		if err != model.ErrorNotFound {
+			return err
+		}
+		return model.ErrorNotFound
+	}
+
+	if !helper.IsHashValid(user.Password, request.OldPassword) {
+		return model.ErrorWrongPassword
+	}
+
+	err = checkPasswordPolicy("new_password", request.NewPassword, user.Username, user.Email)
+	if err != nil {
+		return err
+	}
+
+	return ps.setPassword(user.ID, request.NewPassword)
+}
+
+// ForgotPassword mails a single use reset token. Unknown emails are ignored so
+// the endpoint cannot be used to find out who is registered.
+func (ps *PasswordService) ForgotPassword(request model.UserForgotPasswordRequest) error {
+	user, err := ps.UserRepository.GetByEmail(request.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	err = ps.PasswordResetRepository.InvalidateForUser(user.ID)
+	if err != nil {
+		return err
+	}
+
+	token, err := helper.GenerateRandomToken()
+	if err != nil {
+		return err
+	}
+
+	err = ps.PasswordResetRepository.Add(model.PasswordReset{
+		ID:        helper.GenerateID(),
+		UserID:    user.ID,
+		TokenHash: helper.HashToken(token),
+		ExpiresAt: time.Now().Add(helper.PasswordResetDuration),
+	})
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hi %s,\n\nUse this token to reset your MyGram password: %s\n", user.Username, token)
+	if PASSWORD_RESET_URL != "" {
+		body = fmt.Sprintf("Hi %s,\n\nOpen this link to reset your MyGram password: %s?token=%s\n", user.Username, PASSWORD_RESET_URL, token)
+	}
+	body += fmt.Sprintf("\nThe token expires in %d minutes. If you did not ask for a reset you can ignore this email.\n", int(helper.PasswordResetDuration.Minutes()))
+
+	// sent off the request path and only logged on failure, so neither the
+	// response nor its timing shows whether the email is registered
+	go func() {
+		err := ps.Mailer.Send(user.Email, "Reset your MyGram password", body)
+		if err != nil {
+			log.Printf("send password reset email: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+func (ps *PasswordService) ResetPassword(request model.UserResetPasswordRequest) error {
+	reset, err := ps.PasswordResetRepository.GetByHash(helper.HashToken(request.Token))
+	if err != nil {
+		if err != model.ErrorNotFound {
+			return err
+		}
+		return model.ErrorInvalidResetToken
+	}
+
+	if reset.UsedAt != nil || time.Now().After(reset.ExpiresAt) {
+		return model.ErrorInvalidResetToken
+	}
+
+	// checked before the token is used up, so a rejected password can be retried
+	user, err := ps.UserRepository.GetByID(reset.UserID)
+	if err != nil {
+		if err != model.ErrorNotFound {
+			return err
+		}
+		return model.ErrorInvalidResetToken
+	}
+
+	err = checkPasswordPolicy("new_password", request.NewPassword, user.Username, user.Email)
+	if err != nil {
+		return err
+	}
+
+	consumed, err := ps.PasswordResetRepository.MarkUsed(reset.ID)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return model.ErrorInvalidResetToken
+	}
+
+	err = ps.setPassword(reset.UserID, request.NewPassword)
+	if err != nil {
+		if err != model.ErrorNotFound {
+			return err
+		}
+		return model.ErrorInvalidResetToken
+	}
+
+	// proving control of the email is enough to lift a login lockout
+	return ps.LoginGuard.Unlock(accountKey(reset.UserID))
+}
+
+func (ps *PasswordService) setPassword(userID string, password string) error {
+	hashPassword, err := helper.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	err = ps.UserRepository.UpdatePassword(userID, hashPassword)
+	if err != nil {
+		return err
+	}
+
+	return revokeAllTokens(ps.RevocationRepository, ps.RefreshTokenRepository, ps.SessionRepository, ps.TokenRepository, userID)
+}
+
+// checkPasswordPolicy turns the policy violations of a new password into
+// errors on the request field that carried it.
+func checkPasswordPolicy(field string, password string, username string, email string) error {
+	violations, err := helper.CheckPasswordPolicy(password, username, email)
+	if err != nil {
+		return err
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	fields := make([]model.FieldError, 0, len(violations))
+	for _, violation := range violations {
+		fields = append(fields, model.FieldError{
+			Field: field,
+			Err:   violation,
+		})
+	}
+
+	return model.PasswordPolicyError{Fields: fields}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"finalProject/helper"
+	"finalProject/model"
+	"finalProject/repository"
+	"strings"
+	"time"
+)
+
+// PERSONAL_ACCESS_TOKEN_MAX_DAYS caps the lifetime of new personal access
+// tokens. Tokens without an expiry get the maximum. 0 allows tokens that
+// never expire.
+var PERSONAL_ACCESS_TOKEN_MAX_DAYS = helper.GetEnvInt("PERSONAL_ACCESS_TOKEN_MAX_DAYS", 0)
+
+type IPersonalAccessTokenService interface {
+	Create(request model.PersonalAccessTokenCreateRequest, userID string) (model.PersonalAccessTokenCreateResponse, error)
+	GetAll(userID string) ([]model.PersonalAccessTokenResponse, error)
+	Revoke(tokenID string, userID string) error
+}
+
+type PersonalAccessTokenService struct {
+	PersonalAccessTokenRepository repository.IPersonalAccessTokenRepository
+}
+
+func NewPersonalAccessTokenService(personalAccessTokenRepository repository.IPersonalAccessTokenRepository) *PersonalAccessTokenService {
+	return &PersonalAccessTokenService{
+		PersonalAccessTokenRepository: personalAccessTokenRepository,
+	}
+}
+
+// Create issues a new token. The plain token is only returned here, the
+// database keeps its hash.
+func (ps *PersonalAccessTokenService) Create(request model.PersonalAccessTokenCreateRequest, userID string) (model.PersonalAccessTokenCreateResponse, error) {
+	scopes, err := normalizeScopes(request.Scopes)
+	if err != nil {
+		return model.PersonalAccessTokenCreateResponse{}, err
+	}
+
+	expiresInDays := request.ExpiresInDays
+	if PERSONAL_ACCESS_TOKEN_MAX_DAYS > 0 {
+		if expiresInDays > PERSONAL_ACCESS_TOKEN_MAX_DAYS {
+			return model.PersonalAccessTokenCreateResponse{}, model.ErrorTokenExpiryTooLong
+		}
+		if expiresInDays == 0 {
+			expiresInDays = PERSONAL_ACCESS_TOKEN_MAX_DAYS
+		}
+	}
+
+	random, err := helper.GenerateRandomToken()
+	if err != nil {
+		return model.PersonalAccessTokenCreateResponse{}, err
+	}
+	token := model.PersonalAccessTokenPrefix + random
+
+	var expiresAt *time.Time
+	if expiresInDays > 0 {
+		expiry := time.Now().AddDate(0, 0, expiresInDays)
+		expiresAt = &expiry
+	}
+
+	newToken := model.PersonalAccessToken{
+		ID:        helper.GenerateID(),
+		UserID:    userID,
+		Name:      request.Name,
+		TokenHash: helper.HashToken(token),
+		Scopes:    strings.Join(scopes, " "),
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	err = ps.PersonalAccessTokenRepository.Add(newToken)
+	if err != nil {
+		return model.PersonalAccessTokenCreateResponse{}, err
+	}
+
+	return model.PersonalAccessTokenCreateResponse{
+		ID:        newToken.ID,
+		Name:      newToken.Name,
+		Token:     token,
+		Scopes:    scopes,
+		ExpiresAt: newToken.ExpiresAt,
+		CreatedAt: newToken.CreatedAt,
+	}, nil
+}
+
+func (ps *PersonalAccessTokenService) GetAll(userID string) ([]model.PersonalAccessTokenResponse, error) {
+	tokens := []model.PersonalAccessTokenResponse{}
+
+	res, err := ps.PersonalAccessTokenRepository.FindByUser(userID)
+	if err != nil {
+		return []model.PersonalAccessTokenResponse{}, err
+	}
+
+	for _, token := range res {
+		tokens = append(tokens, model.PersonalAccessTokenResponse{
+			ID:         token.ID,
+			Name:       token.Name,
+			Scopes:     strings.Fields(token.Scopes),
+			ExpiresAt:  token.ExpiresAt,
+			LastUsedAt: token.LastUsedAt,
+			CreatedAt:  token.CreatedAt,
+		})
+	}
+
+	return tokens, nil
+}
+
+func (ps *PersonalAccessTokenService) Revoke(tokenID string, userID string) error {
+	return ps.PersonalAccessTokenRepository.Revoke(tokenID, userID)
+}
+
+// normalizeScopes rejects unknown scopes and drops duplicates.
+func normalizeScopes(requested []string) ([]string, error) {
+	scopes := []string{}
+	seen := map[string]bool{}
+
+	for _, scope := range requested {
+		known := false
+		for _, allowed := range model.PersonalAccessTokenScopes {
+			if scope == allowed {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, model.ErrorInvalidScope
+		}
+
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if len(scopes) == 0 {
+		return nil, model.ErrorInvalidScope
+	}
+
+	return scopes, nil
+}
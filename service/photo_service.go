@@ -4,36 +4,55 @@ import (
 	"finalProject/helper"
 	"finalProject/model"
 	"finalProject/repository"
+	"log"
 )
 
 type IPhotoService interface {
 	Create(request model.PhotoRequest, userID string) (model.PhotoCreateResponse, error)
-	GetAllPhoto() ([]model.PhotoResponse, error)
-	GetOnePhoto(photoID string) (model.PhotoResponse, error)
-	DeletePhoto(photoID string, userID string) error
-	UpdatePhoto(request model.PhotoRequest, userID string, photoID string) (model.PhotoResponse, error)
+	GetAllPhoto(viewerID string) ([]model.PhotoAllResponse, error)
+	GetOnePhoto(photoID string, viewerID string) (model.PhotoResponse, error)
+	DeletePhoto(photoID string, userID string, role string) error
+	UpdatePhoto(request model.PhotoRequest, userID string, role string, photoID string) (model.PhotoResponse, error)
 }
 
 type PhotoService struct {
 	PhotoRepository   repository.IPhotoRepository
 	CommentRepository repository.ICommentRepository
+	BlockRepository   repository.IBlockRepository
+	MuteRepository    repository.IMuteRepository
+	FollowRepository  repository.IFollowRepository
+	FeedRepository    repository.IFeedRepository
+	UserRepository    repository.IUserRepository
 }
 
-func NewPhotoService(photoRepository repository.IPhotoRepository, Commentrepository repository.ICommentRepository) *PhotoService {
+func NewPhotoService(photoRepository repository.IPhotoRepository, Commentrepository repository.ICommentRepository, blockRepository repository.IBlockRepository, muteRepository repository.IMuteRepository, followRepository repository.IFollowRepository, feedRepository repository.IFeedRepository, userRepository repository.IUserRepository) *PhotoService {
 	return &PhotoService{
 		PhotoRepository:   photoRepository,
 		CommentRepository: Commentrepository,
+		BlockRepository:   blockRepository,
+		MuteRepository:    muteRepository,
+		FollowRepository:  followRepository,
+		FeedRepository:    feedRepository,
+		UserRepository:    userRepository,
 	}
 }
 
+// Create also adds the photo to the followers' feeds. The photo is kept when
+// that fails, it only misses from those feeds.
 func (ps *PhotoService) Create(request model.PhotoRequest, userID string) (model.PhotoCreateResponse, error) {
 	PhotoID := helper.GenerateID()
 
+	contentRating := request.ContentRating
+	if contentRating == "" {
+		contentRating = model.ContentRatingGeneral
+	}
+
 	NewPhoto := model.Photo{
-		PhotoID:  PhotoID,
-		Title:    request.Title,
-		PhotoUrl: request.PhotoUrl,
-		UserID:   userID,
+		PhotoID:       PhotoID,
+		Title:         request.Title,
+		PhotoUrl:      request.PhotoUrl,
+		ContentRating: contentRating,
+		UserID:        userID,
 	}
 
 	err := ps.PhotoRepository.Add(NewPhoto)
@@ -45,20 +64,39 @@ func (ps *PhotoService) Create(request model.PhotoRequest, userID string) (model
 		return model.PhotoCreateResponse{}, model.ErrorNotFound
 	}
 
+	err = fanOutPhoto(ps.FeedRepository, ps.FollowRepository, NewPhoto)
+	if err != nil {
+		log.Printf("fan out photo %s: %v", NewPhoto.PhotoID, err)
+	}
+
 	response := model.PhotoCreateResponse{
-		PhotoID:   NewPhoto.PhotoID,
-		Title:     NewPhoto.Title,
-		PhotoUrl:  NewPhoto.PhotoUrl,
-		UserID:    NewPhoto.UserID,
-		CreatedAt: NewPhoto.CreatedAt,
+		PhotoID:       NewPhoto.PhotoID,
+		Title:         NewPhoto.Title,
+		PhotoUrl:      NewPhoto.PhotoUrl,
+		ContentRating: NewPhoto.ContentRating,
+		UserID:        NewPhoto.UserID,
+		CreatedAt:     NewPhoto.CreatedAt,
 	}
 	return response, nil
 }
 
-func (ps *PhotoService) GetAllPhoto() ([]model.PhotoAllResponse, error) {
+// GetAllPhoto leaves out the photos of users the viewer blocked, muted or
+// was blocked by, of private accounts the viewer does not follow, and mature
+// photos for viewers under the mature content age.
+func (ps *PhotoService) GetAllPhoto(viewerID string) ([]model.PhotoAllResponse, error) {
 	photoResults := []model.PhotoAllResponse{}
 
-	res, err := ps.PhotoRepository.FindAll()
+	hidden, err := hiddenUserIDs(ps.BlockRepository, ps.MuteRepository, viewerID)
+	if err != nil {
+		return []model.PhotoAllResponse{}, err
+	}
+
+	mature, err := canViewMature(ps.UserRepository, viewerID)
+	if err != nil {
+		return []model.PhotoAllResponse{}, err
+	}
+
+	res, err := ps.PhotoRepository.FindAll(viewerID, hidden, mature)
 
 	if err != nil {
 		return []model.PhotoAllResponse{}, err
@@ -66,19 +104,24 @@ func (ps *PhotoService) GetAllPhoto() ([]model.PhotoAllResponse, error) {
 
 	for _, reqRes := range res {
 		photoResults = append(photoResults, model.PhotoAllResponse{
-			PhotoID:   reqRes.PhotoID,
-			Title:     reqRes.Title,
-			PhotoUrl:  reqRes.PhotoUrl,
-			UserID:    reqRes.UserID,
-			CreatedAt: reqRes.CreatedAt,
-			UpdatedAt: reqRes.UpdatedAt,
+			PhotoID:       reqRes.PhotoID,
+			Title:         reqRes.Title,
+			PhotoUrl:      reqRes.PhotoUrl,
+			ContentRating: reqRes.ContentRating,
+			UserID:        reqRes.UserID,
+			CreatedAt:     reqRes.CreatedAt,
+			UpdatedAt:     reqRes.UpdatedAt,
 		})
 	}
 
 	return photoResults, nil
 }
 
-func (ps *PhotoService) GetOnePhoto(photoID string) (model.PhotoResponse, error) {
+// GetOnePhoto hides the photo when the viewer and the owner blocked each
+// other or when it is mature and the viewer too young, refuses it when the
+// owner is a private account the viewer does not follow, and leaves out
+// comments from users hidden from the viewer.
+func (ps *PhotoService) GetOnePhoto(photoID string, viewerID string) (model.PhotoResponse, error) {
 	photoRequest, err := ps.PhotoRepository.GetOne(photoID)
 	if err != nil {
 		if err == model.ErrorNotFound {
@@ -88,26 +131,57 @@ func (ps *PhotoService) GetOnePhoto(photoID string) (model.PhotoResponse, error)
 		return model.PhotoResponse{}, model.ErrorNotFound
 	}
 
+	err = checkContentRating(ps.UserRepository, photoRequest, viewerID)
+	if err != nil {
+		return model.PhotoResponse{}, err
+	}
+
+	blocked, err := ps.BlockRepository.IsBlocked(viewerID, photoRequest.UserID)
+	if err != nil {
+		return model.PhotoResponse{}, err
+	}
+	if blocked {
+		return model.PhotoResponse{}, model.ErrorNotFound
+	}
+
+	err = checkVisible(ps.FollowRepository, photoRequest.UserID, viewerID)
+	if err != nil {
+		return model.PhotoResponse{}, err
+	}
+
+	hidden, err := hiddenUserIDs(ps.BlockRepository, ps.MuteRepository, viewerID)
+	if err != nil {
+		return model.PhotoResponse{}, err
+	}
+	hiddenSet := map[string]bool{}
+	for _, userID := range hidden {
+		hiddenSet[userID] = true
+	}
+
 	comments := []model.Comment{}
-	commentsResp, err := ps.CommentRepository.FindCommentByPhoto(photoID)
+	commentsResp, err := ps.CommentRepository.FindCommentByPhoto(photoID, viewerID)
 	for _, comment := range commentsResp {
+		if hiddenSet[comment.UserID] {
+			continue
+		}
 		comments = append(comments, model.Comment(comment))
 	}
 	if err != nil {
 		return model.PhotoResponse{}, err
 	}
 	return model.PhotoResponse{
-		PhotoID:   photoRequest.PhotoID,
-		Title:     photoRequest.Title,
-		PhotoUrl:  photoRequest.PhotoUrl,
-		UserID:    photoRequest.UserID,
-		Comments:  comments,
-		CreatedAt: photoRequest.CreatedAt,
-		UpdatedAt: photoRequest.UpdatedAt,
+		PhotoID:       photoRequest.PhotoID,
+		Title:         photoRequest.Title,
+		PhotoUrl:      photoRequest.PhotoUrl,
+		ContentRating: photoRequest.ContentRating,
+		UserID:        photoRequest.UserID,
+		Comments:      comments,
+		CreatedAt:     photoRequest.CreatedAt,
+		UpdatedAt:     photoRequest.UpdatedAt,
 	}, nil
 }
 
-func (ps *PhotoService) UpdatePhoto(request model.PhotoRequest, userID string, photoID string) (model.PhotoResponse, error) {
+func (ps *PhotoService) UpdatePhoto(request model.PhotoRequest, userID string, role string, photoID string) (model.PhotoResponse, error) {
 	findPhoto, err := ps.PhotoRepository.GetOne(photoID)
 	if err != nil {
 		if err != model.ErrorNotFound {
@@ -116,13 +190,14 @@ func (ps *PhotoService) UpdatePhoto(request model.PhotoRequest, userID string, p
 		return model.PhotoResponse{}, model.ErrorNotFound
 	}
 
-	if userID != findPhoto.UserID {
+	if userID != findPhoto.UserID && !model.CanModerate(role) {
 		return model.PhotoResponse{}, model.ErrorForbiddenAccess
 	}
 
 	updateReq := model.Photo{
-		Title:    request.Title,
-		PhotoUrl: request.PhotoUrl,
+		Title:         request.Title,
+		PhotoUrl:      request.PhotoUrl,
+		ContentRating: request.ContentRating,
 	}
 
 	res, err := ps.PhotoRepository.PhotoUpdate(updateReq, photoID)
@@ -131,15 +206,18 @@ func (ps *PhotoService) UpdatePhoto(request model.PhotoRequest, userID string, p
 	}
 
 	return model.PhotoResponse{
-		PhotoID:   res.PhotoID,
-		Title:     res.Title,
-		PhotoUrl:  res.PhotoUrl,
-		UserID:    res.UserID,
-		UpdatedAt: res.UpdatedAt,
+		PhotoID:       res.PhotoID,
+		Title:         res.Title,
+		PhotoUrl:      res.PhotoUrl,
+		ContentRating: res.ContentRating,
+		UserID:        res.UserID,
+		UpdatedAt:     res.UpdatedAt,
 	}, nil
 }
 
-func (ps *PhotoService) DeletePhoto(photoID string, userID string) error {
+// DeletePhoto moves the photo to the trash. A photo removed by a moderator is
+// marked with the moderator's ID, so it stays out of the owner's trash.
+func (ps *PhotoService) DeletePhoto(photoID string, userID string, role string) error {
 	findPhoto, err := ps.PhotoRepository.GetOne(photoID)
 	if err != nil {
 		if err != model.ErrorNotFound {
@@ -148,11 +226,11 @@ func (ps *PhotoService) DeletePhoto(photoID string, userID string) error {
 		return model.ErrorNotFound
 	}
 
-	if userID != findPhoto.UserID {
+	if userID != findPhoto.UserID && !model.CanModerate(role) {
 		return model.ErrorForbiddenAccess
 	}
 
-	err = ps.PhotoRepository.DeletePhoto(photoID)
+	err = ps.PhotoRepository.DeletePhoto(photoID, userID)
 	if err != nil {
 		return err
 	}
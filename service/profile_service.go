@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"finalProject/model"
+	"finalProject/repository"
+
+	"gorm.io/gorm"
+)
+
+// profileLatestPhotos is how many photos a profile shows.
+const profileLatestPhotos = 9
+
+type IProfileService interface {
+	GetProfile(username string, viewerID string) (model.ProfileResponse, error)
+}
+
+type ProfileService struct {
+	UserRepository        repository.IUserRepository
+	PhotoRepository       repository.IPhotoRepository
+	CommentRepository     repository.ICommentRepository
+	SocialMediaRepository repository.ISocialMediaRepository
+	FollowRepository      repository.IFollowRepository
+	BlockRepository       repository.IBlockRepository
+}
+
+func NewProfileService(userRepository repository.IUserRepository, photoRepository repository.IPhotoRepository, commentRepository repository.ICommentRepository, socialMediaRepository repository.ISocialMediaRepository, followRepository repository.IFollowRepository, blockRepository repository.IBlockRepository) *ProfileService {
+	return &ProfileService{
+		UserRepository:        userRepository,
+		PhotoRepository:       photoRepository,
+		CommentRepository:     commentRepository,
+		SocialMediaRepository: socialMediaRepository,
+		FollowRepository:      followRepository,
+		BlockRepository:       blockRepository,
+	}
+}
+
+// GetProfile hides the profile from users the owner blocked or was blocked by,
+// and refuses it to users who do not follow a private account. The latest
+// photos leave out mature photos for viewers too young for them.
+func (ps *ProfileService) GetProfile(username string, viewerID string) (model.ProfileResponse, error) {
+	user, err := ps.UserRepository.GetByUsername(username)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.ProfileResponse{}, err
+		}
+		return model.ProfileResponse{}, model.ErrorNotFound
+	}
+
+	blocked, err := ps.BlockRepository.IsBlocked(viewerID, user.ID)
+	if err != nil {
+		return model.ProfileResponse{}, err
+	}
+	if blocked {
+		return model.ProfileResponse{}, model.ErrorNotFound
+	}
+
+	err = checkVisible(ps.FollowRepository, user.ID, viewerID)
+	if err != nil {
+		return model.ProfileResponse{}, err
+	}
+
+	photoCount, err := ps.PhotoRepository.CountByUser(user.ID)
+	if err != nil {
+		return model.ProfileResponse{}, err
+	}
+
+	commentCount, err := ps.CommentRepository.CountByUser(user.ID)
+	if err != nil {
+		return model.ProfileResponse{}, err
+	}
+
+	followerCount, err := ps.FollowRepository.CountFollowers(user.ID)
+	if err != nil {
+		return model.ProfileResponse{}, err
+	}
+
+	followingCount, err := ps.FollowRepository.CountFollowing(user.ID)
+	if err != nil {
+		return model.ProfileResponse{}, err
+	}
+
+	socialMedias, err := ps.SocialMediaRepository.FindByUser(user.ID)
+	if err != nil {
+		return model.ProfileResponse{}, err
+	}
+
+	mature, err := canViewMature(ps.UserRepository, viewerID)
+	if err != nil {
+		return model.ProfileResponse{}, err
+	}
+
+	photos, err := ps.PhotoRepository.FindLatestByUser(user.ID, profileLatestPhotos, mature)
+	if err != nil {
+		return model.ProfileResponse{}, err
+	}
+
+	profile := model.ProfileResponse{
+		ID:             user.ID,
+		Username:       user.Username,
+		IsPrivate:      user.IsPrivate,
+		JoinedAt:       user.CreatedAt,
+		PhotoCount:     photoCount,
+		CommentCount:   commentCount,
+		FollowerCount:  followerCount,
+		FollowingCount: followingCount,
+		SocialMedias:   make([]model.ProfileSocialMediaResponse, 0, len(socialMedias)),
+		LatestPhotos:   make([]model.ProfilePhotoResponse, 0, len(photos)),
+	}
+
+	for _, socialMedia := range socialMedias {
+		profile.SocialMedias = append(profile.SocialMedias, model.ProfileSocialMediaResponse{
+			Name:           socialMedia.Name,
+			SocialMediaUrl: socialMedia.SocialMediaUrl,
+		})
+	}
+
+	for _, photo := range photos {
+		profile.LatestPhotos = append(profile.LatestPhotos, model.ProfilePhotoResponse{
+			PhotoID:       photo.PhotoID,
+			Title:         photo.Title,
+			PhotoUrl:      photo.PhotoUrl,
+			ContentRating: photo.ContentRating,
+			CreatedAt:     photo.CreatedAt,
+		})
+	}
+
+	return profile, nil
+}
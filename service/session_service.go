@@ -0,0 +1,59 @@
+package service
+
+import (
+	"finalProject/helper"
+	"finalProject/model"
+	"finalProject/repository"
+	"time"
+)
+
+type ISessionService interface {
+	GetAll(userID string, currentSessionID string) ([]model.SessionResponse, error)
+	Revoke(sessionID string, userID string) error
+}
+
+type SessionService struct {
+	SessionRepository      repository.ISessionRepository
+	RefreshTokenRepository repository.IRefreshTokenRepository
+}
+
+func NewSessionService(sessionRepository repository.ISessionRepository, refreshTokenRepository repository.IRefreshTokenRepository) *SessionService {
+	return &SessionService{
+		SessionRepository:      sessionRepository,
+		RefreshTokenRepository: refreshTokenRepository,
+	}
+}
+
+// GetAll lists the sessions that can still be used. A session nobody has seen
+// for longer than a refresh token lives has no valid token left.
+func (ss *SessionService) GetAll(userID string, currentSessionID string) ([]model.SessionResponse, error) {
+	sessions, err := ss.SessionRepository.FindActiveByUser(userID, time.Now().Add(-helper.RefreshTokenDuration))
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]model.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, model.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			Current:    session.ID == currentSessionID,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+		})
+	}
+
+	return response, nil
+}
+
+// Revoke ends a session. Its access tokens stop working right away because
+// the auth middleware checks the session, its refresh tokens are revoked.
+func (ss *SessionService) Revoke(sessionID string, userID string) error {
+	err := ss.SessionRepository.Revoke(sessionID, userID)
+	if err != nil {
+		return err
+	}
+
+	return ss.RefreshTokenRepository.RevokeFamily(sessionID)
+}
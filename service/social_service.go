@@ -9,18 +9,20 @@ import (
 
 type ISocialMedia interface {
 	Create(request model.SocialMediaCreateRequest, userID string) (model.SocialMediaCreateResponse, error)
-	GetAll() ([]model.SocialMediaResponse, error)
-	Update(updateReq model.SocialMediaUpdateRequest, SocialID string, userID string) (model.SocialMediaUpdateResponse, error)
-	GetOne(socialID string) (model.SocialMediaResponse, error)
+	GetAll(viewerID string) ([]model.SocialMediaResponse, error)
+	Update(updateReq model.SocialMediaUpdateRequest, SocialID string, userID string, role string) (model.SocialMediaUpdateResponse, error)
+	GetOne(socialID string, viewerID string) (model.SocialMediaResponse, error)
 }
 
 type SocialMediaService struct {
 	SocialMediaRepository repository.ISocialMediaRepository
+	FollowRepository      repository.IFollowRepository
 }
 
-func NewSocialMediaService(socialMediaRepository repository.ISocialMediaRepository) *SocialMediaService {
+func NewSocialMediaService(socialMediaRepository repository.ISocialMediaRepository, followRepository repository.IFollowRepository) *SocialMediaService {
 	return &SocialMediaService{
 		SocialMediaRepository: socialMediaRepository,
+		FollowRepository:      followRepository,
 	}
 }
 
@@ -52,10 +54,12 @@ func (ss *SocialMediaService) Create(request model.SocialMediaCreateRequest, use
 	return response, nil
 }
 
-func (ss *SocialMediaService) GetAll() ([]model.SocialMediaResponse, error) {
+// GetAll leaves out the social media of private accounts the viewer does not
+// follow.
+func (ss *SocialMediaService) GetAll(viewerID string) ([]model.SocialMediaResponse, error) {
 	SocialMediaRes := []model.SocialMediaResponse{}
 
-	res, err := ss.SocialMediaRepository.Get()
+	res, err := ss.SocialMediaRepository.Get(viewerID)
 	if err != nil {
 		return []model.SocialMediaResponse{}, err
 	}
@@ -73,7 +77,7 @@ func (ss *SocialMediaService) GetAll() ([]model.SocialMediaResponse, error) {
 	return SocialMediaRes, nil
 }
 
-func (ss *SocialMediaService) Update(updateReq model.SocialMediaUpdateRequest, SocialID string, userID string) (model.SocialMediaUpdateResponse, error) {
+func (ss *SocialMediaService) Update(updateReq model.SocialMediaUpdateRequest, SocialID string, userID string, role string) (model.SocialMediaUpdateResponse, error) {
 	getId, err := ss.SocialMediaRepository.GetOne(SocialID)
 	if err != nil {
 		if err != model.ErrorNotFound {
@@ -82,7 +86,7 @@ func (ss *SocialMediaService) Update(updateReq model.SocialMediaUpdateRequest, S
 		return model.SocialMediaUpdateResponse{}, model.ErrorNotFound
 	}
 
-	if getId.UserID != userID {
+	if getId.UserID != userID && !model.CanModerate(role) {
 		return model.SocialMediaUpdateResponse{}, model.ErrorForbiddenAccess
 	}
 
@@ -101,12 +105,14 @@ func (ss *SocialMediaService) Update(updateReq model.SocialMediaUpdateRequest, S
 		SocialID:       res.SocialID,
 		Name:           res.Name,
 		SocialMediaUrl: res.SocialMediaUrl,
-		UserID:         userID,
+		UserID:         getId.UserID,
 		UpdatedAt:      res.UpdatedAt,
 	}, nil
 }
 
-func (ss *SocialMediaService) GetOne(socialID string) (model.SocialMediaResponse, error) {
+// GetOne refuses the social media of a private account the viewer does not
+// follow.
+func (ss *SocialMediaService) GetOne(socialID string, viewerID string) (model.SocialMediaResponse, error) {
 	getOne, err := ss.SocialMediaRepository.GetOne(socialID)
 
 	if err != nil {
@@ -116,6 +122,11 @@ func (ss *SocialMediaService) GetOne(socialID string) (model.SocialMediaResponse
 		return model.SocialMediaResponse{}, model.ErrorNotFound
 	}
 
+	err = checkVisible(ss.FollowRepository, getOne.UserID, viewerID)
+	if err != nil {
+		return model.SocialMediaResponse{}, err
+	}
+
 	return model.SocialMediaResponse{
 		SocialID:       getOne.SocialID,
 		Name:           getOne.Name,
@@ -126,7 +137,9 @@ func (ss *SocialMediaService) GetOne(socialID string) (model.SocialMediaResponse
 	}, nil
 }
 
-func (ss *SocialMediaService) Delete(socialID string, userID string) error {
+// Delete moves the social media to the trash. One removed by a moderator is
+// marked with the moderator's ID, so it stays out of the owner's trash.
+func (ss *SocialMediaService) Delete(socialID string, userID string, role string) error {
 	getSocialId, err := ss.SocialMediaRepository.GetOne(socialID)
 
 	if err != nil {
@@ -136,11 +149,11 @@ func (ss *SocialMediaService) Delete(socialID string, userID string) error {
 		return model.ErrorNotFound
 	}
 
-	if getSocialId.UserID != userID {
+	if getSocialId.UserID != userID && !model.CanModerate(role) {
 		return model.ErrorForbiddenAccess
 	}
 
-	err = ss.SocialMediaRepository.Delete(socialID)
+	err = ss.SocialMediaRepository.Delete(socialID, userID)
 
 	if err != nil {
 		return err
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"finalProject/helper"
+	"finalProject/model"
+	"finalProject/repository"
+	"log"
+	"time"
+)
+
+var (
+	// TRASH_RETENTION is how long deleted accounts and content can be
+	// restored before they are purged.
+	TRASH_RETENTION = helper.GetEnvDuration("TRASH_RETENTION", 30*24*time.Hour)
+	// TRASH_PURGE_INTERVAL is how often the expired trash is purged.
+	TRASH_PURGE_INTERVAL = helper.GetEnvDuration("TRASH_PURGE_INTERVAL", time.Hour)
+)
+
+type ITrashService interface {
+	GetTrash(userID string) (model.TrashResponse, error)
+	RestorePhoto(photoID string, userID string, role string) error
+	RestoreComment(commentID string, userID string, role string) error
+	RestoreSocialMedia(socialID string, userID string, role string) error
+	RestoreUser(userID string) error
+	Run()
+}
+
+type TrashService struct {
+	UserRepository        repository.IUserRepository
+	PhotoRepository       repository.IPhotoRepository
+	CommentRepository     repository.ICommentRepository
+	SocialMediaRepository repository.ISocialMediaRepository
+}
+
+func NewTrashService(userRepository repository.IUserRepository, photoRepository repository.IPhotoRepository, commentRepository repository.ICommentRepository, socialMediaRepository repository.ISocialMediaRepository) *TrashService {
+	return &TrashService{
+		UserRepository:        userRepository,
+		PhotoRepository:       photoRepository,
+		CommentRepository:     commentRepository,
+		SocialMediaRepository: socialMediaRepository,
+	}
+}
+
+// GetTrash lists what the user deleted within TRASH_RETENTION.
+func (ts *TrashService) GetTrash(userID string) (model.TrashResponse, error) {
+	since := trashCutoff()
+
+	photos, err := ts.PhotoRepository.FindDeleted(userID, since)
+	if err != nil {
+		return model.TrashResponse{}, err
+	}
+
+	comments, err := ts.CommentRepository.FindDeleted(userID, since)
+	if err != nil {
+		return model.TrashResponse{}, err
+	}
+
+	socialMedias, err := ts.SocialMediaRepository.FindDeleted(userID, since)
+	if err != nil {
+		return model.TrashResponse{}, err
+	}
+
+	response := model.TrashResponse{
+		Photos:       []model.TrashPhotoResponse{},
+		Comments:     []model.TrashCommentResponse{},
+		SocialMedias: []model.TrashSocialMediaResponse{},
+	}
+
+	for _, photo := range photos {
+		response.Photos = append(response.Photos, model.TrashPhotoResponse{
+			PhotoID:   photo.PhotoID,
+			Title:     photo.Title,
+			PhotoUrl:  photo.PhotoUrl,
+			DeletedAt: photo.DeletedAt.Time,
+			PurgeAt:   photo.DeletedAt.Time.Add(TRASH_RETENTION),
+		})
+	}
+
+	for _, comment := range comments {
+		response.Comments = append(response.Comments, model.TrashCommentResponse{
+			CommentID: comment.CommentID,
+			Message:   comment.Message,
+			PhotoID:   comment.PhotoID,
+			DeletedAt: comment.DeletedAt.Time,
+			PurgeAt:   comment.DeletedAt.Time.Add(TRASH_RETENTION),
+		})
+	}
+
+	for _, socialMedia := range socialMedias {
+		response.SocialMedias = append(response.SocialMedias, model.TrashSocialMediaResponse{
+			SocialID:       socialMedia.SocialID,
+			Name:           socialMedia.Name,
+			SocialMediaUrl: socialMedia.SocialMediaUrl,
+			DeletedAt:      socialMedia.DeletedAt.Time,
+			PurgeAt:        socialMedia.DeletedAt.Time.Add(TRASH_RETENTION),
+		})
+	}
+
+	return response, nil
+}
+
+// RestorePhoto brings the photo back with the comments deleted along with it.
+// Trash the user cannot restore looks empty.
+func (ts *TrashService) RestorePhoto(photoID string, userID string, role string) error {
+	photo, err := ts.PhotoRepository.GetDeleted(photoID, trashCutoff())
+	if err != nil {
+		return err
+	}
+
+	if !canRestore(photo.UserID, photo.DeletedBy, userID, role) {
+		return model.ErrorNotFound
+	}
+
+	return ts.PhotoRepository.Restore(photoID, photo.DeletedAt.Time)
+}
+
+// RestoreComment is refused while the photo of the comment is in the trash,
+// the comment comes back with the photo.
+func (ts *TrashService) RestoreComment(commentID string, userID string, role string) error {
+	comment, err := ts.CommentRepository.GetDeleted(commentID, trashCutoff())
+	if err != nil {
+		return err
+	}
+
+	if !canRestore(comment.UserID, comment.DeletedBy, userID, role) {
+		return model.ErrorNotFound
+	}
+
+	_, err = ts.PhotoRepository.GetOne(comment.PhotoID)
+	if err != nil {
+		return err
+	}
+
+	return ts.CommentRepository.Restore(commentID)
+}
+
+func (ts *TrashService) RestoreSocialMedia(socialID string, userID string, role string) error {
+	socialMedia, err := ts.SocialMediaRepository.GetDeleted(socialID, trashCutoff())
+	if err != nil {
+		return err
+	}
+
+	if !canRestore(socialMedia.UserID, socialMedia.DeletedBy, userID, role) {
+		return model.ErrorNotFound
+	}
+
+	return ts.SocialMediaRepository.Restore(socialID)
+}
+
+// RestoreUser brings back a deleted account with the content deleted along
+// with it. The user signs in again with their password.
+func (ts *TrashService) RestoreUser(userID string) error {
+	user, err := ts.UserRepository.GetDeleted(userID, trashCutoff())
+	if err != nil {
+		return err
+	}
+
+	return ts.UserRepository.Restore(userID, user.DeletedAt.Time)
+}
+
+// Run purges what stayed in the trash longer than TRASH_RETENTION, every
+// TRASH_PURGE_INTERVAL until the process exits.
+func (ts *TrashService) Run() {
+	ticker := time.NewTicker(TRASH_PURGE_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		ts.purge()
+		<-ticker.C
+	}
+}
+
+// purge removes users first, which takes their content with them, then
+// photos with all of their comments.
+func (ts *TrashService) purge() {
+	before := trashCutoff()
+
+	err := ts.UserRepository.PurgeDeleted(before)
+	if err != nil {
+		log.Printf("purge deleted users: %v", err)
+	}
+
+	err = ts.PhotoRepository.PurgeDeleted(before)
+	if err != nil {
+		log.Printf("purge deleted photos: %v", err)
+	}
+
+	err = ts.CommentRepository.PurgeDeleted(before)
+	if err != nil {
+		log.Printf("purge deleted comments: %v", err)
+	}
+
+	err = ts.SocialMediaRepository.PurgeDeleted(before)
+	if err != nil {
+		log.Printf("purge deleted social media: %v", err)
+	}
+}
+
+// canRestore lets the owner restore what they deleted and moderators restore
+// anything, including what they removed themselves.
+func canRestore(ownerID string, deletedBy string, userID string, role string) bool {
+	if model.CanModerate(role) {
+		return true
+	}
+	return ownerID == userID && deletedBy == userID
+}
+
+func trashCutoff() time.Time {
+	return time.Now().Add(-TRASH_RETENTION)
+}
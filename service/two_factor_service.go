@@ -0,0 +1,171 @@
+package service
+
+import (
+	"finalProject/helper"
+	"finalProject/model"
+	"finalProject/repository"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// loginChallengePurpose marks the token handed out after the password step of
+// a login on an account with two-factor authentication.
+const (
+	loginChallengePurpose  = "login_challenge"
+	loginChallengeDuration = 5 * time.Minute
+)
+
+type ITwoFactorService interface {
+	Setup(userID string) (model.TwoFactorSetupResponse, error)
+	Enable(request model.TwoFactorEnableRequest, userID string) (model.TwoFactorEnableResponse, error)
+	Disable(request model.TwoFactorDisableRequest, userID string) error
+}
+
+type TwoFactorService struct {
+	UserRepository         repository.IUserRepository
+	RecoveryCodeRepository repository.IRecoveryCodeRepository
+}
+
+func NewTwoFactorService(userRepository repository.IUserRepository, recoveryCodeRepository repository.IRecoveryCodeRepository) *TwoFactorService {
+	return &TwoFactorService{
+		UserRepository:         userRepository,
+		RecoveryCodeRepository: recoveryCodeRepository,
+	}
+}
+
+// Setup creates a new secret that stays pending until Enable confirms a code
+// from the authenticator app. Calling it again replaces the pending secret.
+func (ts *TwoFactorService) Setup(userID string) (model.TwoFactorSetupResponse, error) {
+	user, err := ts.UserRepository.GetByID(userID)
+	if err != nil {
+		return model.TwoFactorSetupResponse{}, err
+	}
+
+	if user.TOTPEnabledAt != nil {
+		return model.TwoFactorSetupResponse{}, model.ErrorTwoFactorAlreadyEnabled
+	}
+
+	secret, err := helper.GenerateTOTPSecret()
+	if err != nil {
+		return model.TwoFactorSetupResponse{}, err
+	}
+
+	err = ts.UserRepository.SetTOTPSecret(userID, secret)
+	if err != nil {
+		return model.TwoFactorSetupResponse{}, err
+	}
+
+	return model.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: helper.TOTPProvisioningURI(user.Email, secret),
+	}, nil
+}
+
+// Enable turns on two-factor authentication and returns the recovery codes.
+// They are only stored hashed, so this is the only time they can be shown.
+func (ts *TwoFactorService) Enable(request model.TwoFactorEnableRequest, userID string) (model.TwoFactorEnableResponse, error) {
+	user, err := ts.UserRepository.GetByID(userID)
+	if err != nil {
+		return model.TwoFactorEnableResponse{}, err
+	}
+
+	if user.TOTPEnabledAt != nil {
+		return model.TwoFactorEnableResponse{}, model.ErrorTwoFactorAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return model.TwoFactorEnableResponse{}, model.ErrorTwoFactorNotSetUp
+	}
+
+	now := time.Now()
+	step, ok := helper.ValidateTOTP(user.TOTPSecret, request.Code, now)
+	if !ok {
+		return model.TwoFactorEnableResponse{}, model.ErrorInvalidTwoFactorCode
+	}
+
+	codes, err := helper.GenerateRecoveryCodes()
+	if err != nil {
+		return model.TwoFactorEnableResponse{}, err
+	}
+
+	recoveryCodes := make([]model.RecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		recoveryCodes = append(recoveryCodes, model.RecoveryCode{
+			ID:       helper.GenerateID(),
+			UserID:   userID,
+			CodeHash: helper.HashToken(code),
+		})
+	}
+
+	err = ts.RecoveryCodeRepository.Replace(userID, recoveryCodes)
+	if err != nil {
+		return model.TwoFactorEnableResponse{}, err
+	}
+
+	err = ts.UserRepository.EnableTOTP(userID, now, step)
+	if err != nil {
+		return model.TwoFactorEnableResponse{}, err
+	}
+
+	return model.TwoFactorEnableResponse{
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// Disable needs both the password and a second factor, so a stolen access
+// token alone cannot switch two-factor authentication off.
+func (ts *TwoFactorService) Disable(request model.TwoFactorDisableRequest, userID string) error {
+	user, err := ts.UserRepository.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if user.TOTPEnabledAt == nil {
+		return model.ErrorTwoFactorNotEnabled
+	}
+
+	if !helper.IsHashValid(user.Password, request.Password) {
+		return model.ErrorWrongPassword
+	}
+
+	ok, err := verifySecondFactor(ts.UserRepository, ts.RecoveryCodeRepository, user, request.Code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return model.ErrorInvalidTwoFactorCode
+	}
+
+	err = ts.UserRepository.DisableTOTP(userID)
+	if err != nil {
+		return err
+	}
+
+	return ts.RecoveryCodeRepository.DeleteForUser(userID)
+}
+
+// loginChallenge is the login response for an account with two-factor
+// authentication, the tokens are issued once the challenge is answered.
+func loginChallenge(userID string) (model.UserLoginResponse, error) {
+	challenge, err := helper.GeneratePurposeToken(loginChallengePurpose, jwt.MapClaims{
+		"user_id": userID,
+	}, loginChallengeDuration)
+	if err != nil {
+		return model.UserLoginResponse{}, model.ErrorInvalidToken
+	}
+
+	return model.UserLoginResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    challenge,
+	}, nil
+}
+
+// verifySecondFactor accepts either a current TOTP code that has not been used
+// yet or one of the unused recovery codes.
+func verifySecondFactor(userRepository repository.IUserRepository, recoveryCodeRepository repository.IRecoveryCodeRepository, user model.User, code string) (bool, error) {
+	if step, ok := helper.ValidateTOTP(user.TOTPSecret, code, time.Now()); ok {
+		return userRepository.UseTOTPStep(user.ID, step)
+	}
+
+	return recoveryCodeRepository.Use(user.ID, helper.HashToken(helper.NormalizeRecoveryCode(code)))
+}
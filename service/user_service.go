@@ -3,28 +3,85 @@ package service
 import (
 	"errors"
 	"finalProject/helper"
+	"finalProject/mailer"
 	"finalProject/model"
 	"finalProject/repository"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/golang-jwt/jwt"
 	"gorm.io/gorm"
 )
 
+var (
+	// REQUIRE_EMAIL_VERIFICATION=true blocks login until the email is verified.
+	// Accounts created before verification existed start out unverified as well.
+	REQUIRE_EMAIL_VERIFICATION = os.Getenv("REQUIRE_EMAIL_VERIFICATION") == "true"
+
+	// EMAIL_VERIFICATION_URL is the page that accepts the verification token as
+	// the "token" query parameter, for example https://api.example.com/mygram/user/verify
+	EMAIL_VERIFICATION_URL = os.Getenv("EMAIL_VERIFICATION_URL")
+)
+
+const (
+	emailVerificationPurpose = "email_verification"
+
+	maxUserAgentLength = 512
+)
+
 type IUserService interface {
 	Register(userRegisterRequest model.UserRegisterRequest) (*model.UserRegisterResponse, error)
-	Login(userLoginRequest model.UserLoginRequest) (model.UserLoginResponse, error)
+	Login(userLoginRequest model.UserLoginRequest, client model.ClientInfo) (model.UserLoginResponse, error)
+	LoginTwoFactor(request model.UserLoginTwoFactorRequest, client model.ClientInfo) (model.UserLoginResponse, error)
+	Refresh(request model.UserRefreshRequest, client model.ClientInfo) (model.UserLoginResponse, error)
+	Logout(userID string, jti string, sessionID string, expiresAt time.Time) error
+	LogoutAll(userID string) error
+	GetMe(userID string) (model.UserResponse, error)
+	UpdateMe(request model.UserUpdateRequest, userID string) (model.UserResponse, error)
+	DeleteMe(userID string) error
+	VerifyEmail(token string) error
+	ResendVerification(request model.UserResendVerificationRequest) error
 }
 
 type UserService struct {
-	UserRepository repository.UserRepository
+	UserRepository         repository.UserRepository
+	RefreshTokenRepository repository.IRefreshTokenRepository
+	RevocationRepository   repository.IRevocationRepository
+	RecoveryCodeRepository repository.IRecoveryCodeRepository
+	SessionRepository      repository.ISessionRepository
+	TokenRepository        repository.IPersonalAccessTokenRepository
+	Mailer                 mailer.Mailer
+	LoginGuard             *LoginGuard
 }
 
-func NewUserService(userRepository repository.UserRepository) *UserService {
+func NewUserService(userRepository repository.UserRepository, refreshTokenRepository repository.IRefreshTokenRepository, revocationRepository repository.IRevocationRepository, recoveryCodeRepository repository.IRecoveryCodeRepository, sessionRepository repository.ISessionRepository, tokenRepository repository.IPersonalAccessTokenRepository, mail mailer.Mailer, loginGuard *LoginGuard) *UserService {
 	return &UserService{
-		UserRepository: userRepository,
+		UserRepository:         userRepository,
+		RefreshTokenRepository: refreshTokenRepository,
+		RevocationRepository:   revocationRepository,
+		RecoveryCodeRepository: recoveryCodeRepository,
+		SessionRepository:      sessionRepository,
+		TokenRepository:        tokenRepository,
+		Mailer:                 mail,
+		LoginGuard:             loginGuard,
 	}
 }
 
 func (us *UserService) Register(userRegisterRequest model.UserRegisterRequest) (*model.UserRegisterResponse, error) {
+	err := checkPasswordPolicy("password", userRegisterRequest.Password, userRegisterRequest.Username, userRegisterRequest.Email)
+	if err != nil {
+		return &model.UserRegisterResponse{}, err
+	}
+
+	err = us.checkDuplicates(userRegisterRequest.Email, userRegisterRequest.Username)
+	if err != nil {
+		return &model.UserRegisterResponse{}, err
+	}
+
 	id := helper.GenerateID()
 
 	hashPassword, err := helper.Hash(userRegisterRequest.Password)
@@ -33,11 +90,13 @@ func (us *UserService) Register(userRegisterRequest model.UserRegisterRequest) (
 	}
 
 	user := model.User{
-		ID:       id,
-		Username: userRegisterRequest.Username,
-		Email:    userRegisterRequest.Email,
-		Password: hashPassword,
-		Age:      userRegisterRequest.Age,
+		ID:        id,
+		Username:  userRegisterRequest.Username,
+		Email:     userRegisterRequest.Email,
+		Password:  hashPassword,
+		Age:       userRegisterRequest.Age,
+		Role:      model.RoleUser,
+		IsPrivate: isRestrictedAge(userRegisterRequest.Age),
 	}
 
 	res, err := us.UserRepository.Add(user)
@@ -46,36 +105,538 @@ func (us *UserService) Register(userRegisterRequest model.UserRegisterRequest) (
 		return &model.UserRegisterResponse{}, err
 	}
 
+	// the account already exists at this point, a failed email can be resent later
+	err = us.sendVerificationEmail(res)
+	if err != nil {
+		log.Printf("send verification email to user %s: %v", res.ID, err)
+	}
+
 	return &model.UserRegisterResponse{
 		ID:        res.ID,
 		Username:  res.Username,
 		Email:     res.Email,
 		Age:       res.Age,
+		IsPrivate: res.IsPrivate,
 		CreatedAt: res.CreatedAt,
 		UpdatedAt: res.UpdatedAt,
 	}, nil
 }
 
-func (us *UserService) Login(userLoginRequest model.UserLoginRequest) (model.UserLoginResponse, error) {
+func (us *UserService) Login(userLoginRequest model.UserLoginRequest, client model.ClientInfo) (model.UserLoginResponse, error) {
+	clientKey := ipKey(client.IP)
+	err := us.LoginGuard.CheckClient(clientKey)
+	if err != nil {
+		return model.UserLoginResponse{}, err
+	}
 
-	user, err := us.UserRepository.GetByEmail(userLoginRequest.Email)
+	user, err := us.findByIdentifier(userLoginRequest.Identifier)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.UserLoginResponse{}, err
+	}
+
+	key := unknownAccountKey(userLoginRequest.Identifier)
+	if err == nil {
+		key = accountKey(user.ID)
+	}
+
+	err = us.LoginGuard.Check(key)
 	if err != nil {
-		return model.UserLoginResponse{}, model.ErrorInvalidEmailOrPassword
+		return model.UserLoginResponse{}, err
 	}
 
-	if !helper.IsHashValid(user.Password, userLoginRequest.Password) {
+	// unknown accounts and accounts without a password are checked against a
+	// dummy hash, so the response time does not tell them apart
+	hash := user.Password
+	if hash == "" {
+		hash = helper.DummyHash()
+	}
+	valid := helper.IsHashValid(hash, userLoginRequest.Password)
+
+	if user.ID == "" || user.Password == "" || !valid {
+		err = us.LoginGuard.Fail(key, clientKey)
+		if err != nil {
+			return model.UserLoginResponse{}, err
+		}
 		return model.UserLoginResponse{}, model.ErrorInvalidEmailOrPassword
 	}
 
-	token, err := helper.GenerateAccessToken(user.ID, user.Email)
+	us.rehashPassword(user, userLoginRequest.Password)
+
+	if REQUIRE_EMAIL_VERIFICATION && user.EmailVerifiedAt == nil {
+		return model.UserLoginResponse{}, model.ErrorEmailNotVerified
+	}
+
+	// failures are only cleared after the second factor, otherwise knowing the
+	// password would reset the throttle on guessing codes
+	if user.TOTPEnabledAt != nil {
+		return loginChallenge(user.ID)
+	}
+
+	err = us.LoginGuard.Succeed(key)
+	if err != nil {
+		return model.UserLoginResponse{}, err
+	}
+
+	return us.startSession(user, client)
+}
+
+// LoginTwoFactor finishes a login that returned a challenge token by checking
+// a TOTP code or a recovery code. Wrong codes count as failed logins.
+func (us *UserService) LoginTwoFactor(request model.UserLoginTwoFactorRequest, client model.ClientInfo) (model.UserLoginResponse, error) {
+	clientKey := ipKey(client.IP)
+	err := us.LoginGuard.CheckClient(clientKey)
+	if err != nil {
+		return model.UserLoginResponse{}, err
+	}
+
+	claims, err := helper.VerifyPurposeToken(request.ChallengeToken, loginChallengePurpose)
+	if err != nil {
+		return model.UserLoginResponse{}, model.ErrorInvalidChallengeToken
+	}
+	userID, _ := claims["user_id"].(string)
+
+	user, err := us.UserRepository.GetByID(userID)
+	if err != nil {
+		if err != model.ErrorNotFound {
+			return model.UserLoginResponse{}, err
+		}
+		return model.UserLoginResponse{}, model.ErrorInvalidChallengeToken
+	}
+
+	if user.TOTPEnabledAt == nil {
+		return model.UserLoginResponse{}, model.ErrorInvalidChallengeToken
+	}
+
+	key := accountKey(user.ID)
+	err = us.LoginGuard.Check(key)
+	if err != nil {
+		return model.UserLoginResponse{}, err
+	}
+
+	ok, err := verifySecondFactor(&us.UserRepository, us.RecoveryCodeRepository, user, request.Code)
+	if err != nil {
+		return model.UserLoginResponse{}, err
+	}
+	if !ok {
+		err = us.LoginGuard.Fail(key, clientKey)
+		if err != nil {
+			return model.UserLoginResponse{}, err
+		}
+		return model.UserLoginResponse{}, model.ErrorInvalidTwoFactorCode
+	}
+
+	err = us.LoginGuard.Succeed(key)
+	if err != nil {
+		return model.UserLoginResponse{}, err
+	}
+
+	return us.startSession(user, client)
+}
+
+// rehashPassword upgrades a hash made under an older hashing policy while the
+// plain password is at hand. The old hash still works, so failures are only logged.
+func (us *UserService) rehashPassword(user model.User, password string) {
+	if !helper.NeedsRehash(user.Password) {
+		return
+	}
+
+	hashPassword, err := helper.Hash(password)
+	if err != nil {
+		log.Printf("rehash password of user %s: %v", user.ID, err)
+		return
+	}
+
+	err = us.UserRepository.RehashPassword(user.ID, user.Password, hashPassword)
+	if err != nil {
+		log.Printf("rehash password of user %s: %v", user.ID, err)
+	}
+}
+
+// findByIdentifier resolves a login identifier that is either an email or a
+// username. Usernames may contain "@" too, so a miss on email falls back to username.
+func (us *UserService) findByIdentifier(identifier string) (model.User, error) {
+	if strings.Contains(identifier, "@") {
+		user, err := us.UserRepository.GetByEmail(identifier)
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, err
+		}
+	}
+
+	return us.UserRepository.GetByUsername(identifier)
+}
+
+// Refresh rotates a refresh token. Presenting a token that was already rotated
+// means it leaked, so the whole family is revoked and the user must log in again.
+func (us *UserService) Refresh(request model.UserRefreshRequest, client model.ClientInfo) (model.UserLoginResponse, error) {
+	stored, err := us.RefreshTokenRepository.GetByHash(helper.HashToken(request.RefreshToken))
+	if err != nil {
+		if err != model.ErrorNotFound {
+			return model.UserLoginResponse{}, err
+		}
+		return model.UserLoginResponse{}, model.ErrorInvalidRefreshToken
+	}
+
+	if stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return model.UserLoginResponse{}, model.ErrorInvalidRefreshToken
+	}
+
+	if stored.UsedAt != nil {
+		return model.UserLoginResponse{}, us.revokeReusedFamily(stored.FamilyID)
+	}
+
+	rotated, err := us.RefreshTokenRepository.MarkUsed(stored.ID)
+	if err != nil {
+		return model.UserLoginResponse{}, err
+	}
+	if !rotated {
+		return model.UserLoginResponse{}, us.revokeReusedFamily(stored.FamilyID)
+	}
+
+	user, err := us.UserRepository.GetByID(stored.UserID)
+	if err != nil {
+		if err != model.ErrorNotFound {
+			return model.UserLoginResponse{}, err
+		}
+		return model.UserLoginResponse{}, model.ErrorInvalidRefreshToken
+	}
+
+	err = us.refreshSession(stored, client)
+	if err != nil {
+		return model.UserLoginResponse{}, err
+	}
+
+	return us.issueTokens(user, stored.FamilyID)
+}
+
+// refreshSession keeps the session of a refresh token alive. Logins from
+// before sessions were recorded get their session on the first refresh.
+func (us *UserService) refreshSession(stored model.RefreshToken, client model.ClientInfo) error {
+	session, err := us.SessionRepository.GetByID(stored.FamilyID)
+	if err == model.ErrorNotFound {
+		return us.SessionRepository.Add(newSession(stored.FamilyID, stored.UserID, client))
+	}
+	if err != nil {
+		return err
+	}
+
+	if session.RevokedAt != nil || session.UserID != stored.UserID {
+		return model.ErrorInvalidRefreshToken
+	}
+
+	return us.SessionRepository.Touch(session.ID, client.IP)
+}
+
+func (us *UserService) revokeReusedFamily(familyID string) error {
+	err := us.RefreshTokenRepository.RevokeFamily(familyID)
+	if err != nil {
+		return err
+	}
+	return model.ErrorRefreshTokenReused
+}
+
+// startSession records a new login and issues its first tokens.
+func (us *UserService) startSession(user model.User, client model.ClientInfo) (model.UserLoginResponse, error) {
+	session := newSession(helper.GenerateID(), user.ID, client)
+
+	err := us.SessionRepository.Add(session)
+	if err != nil {
+		return model.UserLoginResponse{}, err
+	}
+
+	return us.issueTokens(user, session.ID)
+}
+
+func newSession(sessionID string, userID string, client model.ClientInfo) model.Session {
+	// Postgres refuses invalid UTF-8, so the header is cleaned up and only
+	// cut where a character starts.
+	userAgent := strings.ToValidUTF8(client.UserAgent, "")
+	if len(userAgent) > maxUserAgentLength {
+		cut := maxUserAgentLength
+		for cut > 0 && !utf8.RuneStart(userAgent[cut]) {
+			cut--
+		}
+		userAgent = userAgent[:cut]
+	}
+
+	now := time.Now()
+	return model.Session{
+		ID:         sessionID,
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IP:         client.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+}
+
+func (us *UserService) issueTokens(user model.User, familyID string) (model.UserLoginResponse, error) {
+	token, err := helper.GenerateAccessToken(user.ID, user.Email, user.Role, familyID)
 	if err != nil {
 		return model.UserLoginResponse{}, model.ErrorInvalidToken
 	}
 
+	refreshToken, err := helper.GenerateRandomToken()
+	if err != nil {
+		return model.UserLoginResponse{}, err
+	}
+
+	err = us.RefreshTokenRepository.Add(model.RefreshToken{
+		ID:        helper.GenerateID(),
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: helper.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(helper.RefreshTokenDuration),
+	})
+	if err != nil {
+		return model.UserLoginResponse{}, err
+	}
+
 	return model.UserLoginResponse{
-		Token: token,
+		Token:        token,
+		RefreshToken: refreshToken,
 	}, nil
+}
+
+// Logout ends the current session, revoking the access token in use and the
+// refresh tokens of the same login.
+func (us *UserService) Logout(userID string, jti string, sessionID string, expiresAt time.Time) error {
+	err := us.RevocationRepository.Revoke(jti, userID, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	err = us.SessionRepository.Revoke(sessionID, userID)
+	if err != nil && err != model.ErrorNotFound {
+		return err
+	}
+
+	return us.RefreshTokenRepository.RevokeFamily(sessionID)
+}
+
+// LogoutAll ends every session and revokes every access, refresh and personal
+// access token the user holds.
+func (us *UserService) LogoutAll(userID string) error {
+	return revokeAllTokens(us.RevocationRepository, us.RefreshTokenRepository, us.SessionRepository, us.TokenRepository, userID)
+}
+
+func revokeAllTokens(revocationRepository repository.IRevocationRepository, refreshTokenRepository repository.IRefreshTokenRepository, sessionRepository repository.ISessionRepository, tokenRepository repository.IPersonalAccessTokenRepository, userID string) error {
+	err := revocationRepository.RevokeAllForUser(userID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	err = sessionRepository.RevokeAllForUser(userID)
+	if err != nil {
+		return err
+	}
+
+	err = tokenRepository.RevokeAllForUser(userID)
+	if err != nil {
+		return err
+	}
+
+	return refreshTokenRepository.RevokeAllForUser(userID)
+}
+
+func (us *UserService) GetMe(userID string) (model.UserResponse, error) {
+	user, err := us.UserRepository.GetByID(userID)
+	if err != nil {
+		if err != model.ErrorNotFound {
+			return model.UserResponse{}, err
+		}
+		return model.UserResponse{}, model.ErrorNotFound
+	}
+
+	return toUserResponse(user), nil
+}
+
+// UpdateMe records a changed age as of today. An age set by an admin cannot be
+// changed here. Lowering the age into the restricted range makes the account
+// private again.
+func (us *UserService) UpdateMe(request model.UserUpdateRequest, userID string) (model.UserResponse, error) {
+	current, err := us.UserRepository.GetByID(userID)
+	if err != nil {
+		if err != model.ErrorNotFound {
+			return model.UserResponse{}, err
+		}
+		return model.UserResponse{}, model.ErrorNotFound
+	}
+
+	changedAge := request.Age != currentAge(current)
+	if changedAge && current.AgeLockedAt != nil {
+		return model.UserResponse{}, model.ErrorAgeLocked
+	}
+
+	changedEmail, changedUsername := "", ""
+	if request.Email != current.Email {
+		changedEmail = request.Email
+	}
+	if request.Username != current.Username {
+		changedUsername = request.Username
+	}
+	err = us.checkDuplicates(changedEmail, changedUsername)
+	if err != nil {
+		return model.UserResponse{}, err
+	}
+
+	updateUser := model.User{
+		Username: request.Username,
+		Email:    request.Email,
+	}
+	if changedAge {
+		now := time.Now()
+		updateUser.Age = request.Age
+		updateUser.AgeRecordedAt = &now
+	}
+
+	res, err := us.UserRepository.Update(updateUser, userID)
+	if err != nil {
+		if err != model.ErrorNotFound {
+			return model.UserResponse{}, err
+		}
+		return model.UserResponse{}, model.ErrorNotFound
+	}
+
+	if isRestrictedAge(currentAge(res)) && !res.IsPrivate {
+		err = us.UserRepository.SetPrivate(userID, true)
+		if err != nil {
+			return model.UserResponse{}, err
+		}
+		res.IsPrivate = true
+	}
+
+	if res.Email != current.Email {
+		err = us.UserRepository.SetEmailVerified(userID, nil)
+		if err != nil {
+			return model.UserResponse{}, err
+		}
+		res.EmailVerifiedAt = nil
+
+		err = us.sendVerificationEmail(res)
+		if err != nil {
+			log.Printf("send verification email to user %s: %v", res.ID, err)
+		}
+	}
+
+	return toUserResponse(res), nil
+}
+
+// DeleteMe moves the account with all of its content to the trash, where an
+// admin can restore it for TRASH_RETENTION, and signs it out everywhere.
+func (us *UserService) DeleteMe(userID string) error {
+	err := us.UserRepository.Delete(userID)
+	if err != nil {
+		return err
+	}
+
+	return us.RevocationRepository.RevokeAllForUser(userID, time.Now())
+}
+
+// VerifyEmail confirms the address from a verification link. The link is tied
+// to the email it was sent to, so it stops working once the email changes.
+func (us *UserService) VerifyEmail(token string) error {
+	claims, err := helper.VerifyPurposeToken(token, emailVerificationPurpose)
+	if err != nil {
+		return model.ErrorInvalidVerificationToken
+	}
+
+	userID, _ := claims["user_id"].(string)
+	email, _ := claims["email"].(string)
+
+	user, err := us.UserRepository.GetByID(userID)
+	if err != nil {
+		if err != model.ErrorNotFound {
+			return err
+		}
+		return model.ErrorInvalidVerificationToken
+	}
+
+	if user.Email != email {
+		return model.ErrorInvalidVerificationToken
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	return us.UserRepository.SetEmailVerified(user.ID, &now)
+}
+
+// ResendVerification sends a new link. Unknown and already verified emails are
+// ignored so the endpoint does not reveal who is registered.
+func (us *UserService) ResendVerification(request model.UserResendVerificationRequest) error {
+	user, err := us.UserRepository.GetByEmail(request.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	return us.sendVerificationEmail(user)
+}
+
+func (us *UserService) sendVerificationEmail(user model.User) error {
+	token, err := helper.GeneratePurposeToken(emailVerificationPurpose, jwt.MapClaims{
+		"user_id": user.ID,
+		"email":   user.Email,
+	}, helper.EmailVerificationDuration)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hi %s,\n\nUse this token to verify your MyGram email: %s\n", user.Username, token)
+	if EMAIL_VERIFICATION_URL != "" {
+		body = fmt.Sprintf("Hi %s,\n\nOpen this link to verify your MyGram email: %s?token=%s\n", user.Username, EMAIL_VERIFICATION_URL, token)
+	}
+	body += fmt.Sprintf("\nThe link expires in %d hours.\n", int(helper.EmailVerificationDuration.Hours()))
+
+	return us.Mailer.Send(user.Email, "Verify your MyGram email", body)
+}
+
+func toUserResponse(user model.User) model.UserResponse {
+	return model.UserResponse{
+		ID:            user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		TwoFactor:     user.TOTPEnabledAt != nil,
+		Age:           currentAge(user),
+		Role:          user.Role,
+		IsPrivate:     user.IsPrivate,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+}
+
+// checkDuplicates reports which field is already taken, empty values are skipped.
+func (us *UserService) checkDuplicates(email string, username string) error {
+	if email != "" {
+		exists, err := us.EmailExists(email)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return model.ErrorEmailAlreadyExists
+		}
+	}
+
+	if username != "" {
+		exists, err := us.UserNameExists(username)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return model.ErrorUsernameAlreadyExists
+		}
+	}
 
+	return nil
 }
 
 func (s *UserService) EmailExists(email string) (bool, error) {